@@ -8,14 +8,45 @@ import (
 	"aoc-2025/helpers"
 )
 
+// Params declares the dial, used by both parts
+var Params = []helpers.Param{
+	{Name: "size", Kind: helpers.ParamInt, Default: 100, Usage: "number of positions on the dial"},
+	{Name: "start", Kind: helpers.ParamInt, Default: 50, Usage: "position the dial starts at"},
+}
+
 func Part1(inputFile string) (string, error) {
-	lines, err := helpers.ReadLines(inputFile)
+	return Part1With(inputFile, nil)
+}
+
+func Part2(inputFile string) (string, error) {
+	return Part2With(inputFile, nil)
+}
+
+// dial reads the dial parameters and the rotations
+func dial(inputFile string, params helpers.Params) (size, start int, lines []string, err error) {
+	values, err := helpers.Resolve(Params, params)
+	if err != nil {
+		return 0, 0, nil, err
+	}
+	size, start = values.Int("size"), values.Int("start")
+	if size <= 0 || start < 0 || start >= size {
+		return 0, 0, nil, fmt.Errorf("start %d must be a position on a dial of size %d", start, size)
+	}
+
+	lines, err = helpers.ReadLines(inputFile)
+	if err != nil {
+		return 0, 0, nil, fmt.Errorf("failed to read input: %w", err)
+	}
+	return size, start, lines, nil
+}
+
+func Part1With(inputFile string, params helpers.Params) (string, error) {
+	size, pointer, lines, err := dial(inputFile, params)
 	if err != nil {
-		return "", fmt.Errorf("failed to read input: %w", err)
+		return "", err
 	}
 
 	acc := 0
-	pointer := 50
 	for _, line := range lines {
 		direction := line[0]
 		number, err := strconv.Atoi(line[1:])
@@ -25,9 +56,9 @@ func Part1(inputFile string) (string, error) {
 
 		switch direction {
 		case 'L':
-			pointer = wrap(pointer-number, 100)
+			pointer = wrap(pointer-number, size)
 		case 'R':
-			pointer = wrap(pointer+number, 100)
+			pointer = wrap(pointer+number, size)
 		}
 
 		if pointer == 0 {
@@ -42,14 +73,13 @@ func wrap(value, max int) int {
 	return ((value % max) + max) % max
 }
 
-func Part2(inputFile string) (string, error) {
-	lines, err := helpers.ReadLines(inputFile)
+func Part2With(inputFile string, params helpers.Params) (string, error) {
+	size, pointer, lines, err := dial(inputFile, params)
 	if err != nil {
-		return "", fmt.Errorf("failed to read input: %w", err)
+		return "", err
 	}
 
 	acc := 0
-	pointer := 50
 	for _, line := range lines {
 		direction := line[0]
 		number, err := strconv.Atoi(line[1:])
@@ -66,22 +96,22 @@ func Part2(inputFile string) (string, error) {
 			nonWrapped = pointer - number
 			// Negative number means we crossed 0
 			if nonWrapped < 0 {
-				// If we start at 0 we count full 100s
+				// If we start at 0 we count full turns
 				if pointer == 0 {
-					crossings = number / 100
+					crossings = number / size
 				} else {
 					// Otherwise we need to account for the first partial crossing
-					crossings = (number-pointer)/100 + 1
+					crossings = (number-pointer)/size + 1
 				}
 			}
 		case 'R':
 			nonWrapped = pointer + number
-			if nonWrapped >= 100 {
-				crossings = (nonWrapped) / 100
+			if nonWrapped >= size {
+				crossings = (nonWrapped) / size
 			}
 		}
 
-		pointer = wrap(nonWrapped, 100)
+		pointer = wrap(nonWrapped, size)
 
 		if pointer == 0 && prevPointer != 0 && crossings == 0 {
 			acc += 1
@@ -1,6 +1,11 @@
 package day01
 
-import "testing"
+import (
+	"strconv"
+	"testing"
+
+	"aoc-2025/helpers"
+)
 
 func TestPart1(t *testing.T) {
 	res, err := Part1("input_test.txt")
@@ -24,3 +29,43 @@ func TestPart2(t *testing.T) {
 		t.Errorf("got %s, want %s", res, expected)
 	}
 }
+
+// clicks turns the dial one position at a time and counts every stop at 0
+func clicks(t *testing.T, inputFile string, size, start int) int {
+	lines, err := helpers.ReadLines(inputFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	count, pointer := 0, start
+	for _, line := range lines {
+		n, _ := strconv.Atoi(line[1:])
+		step := 1
+		if line[0] == 'L' {
+			step = size - 1
+		}
+		for range n {
+			pointer = (pointer + step) % size
+			if pointer == 0 {
+				count++
+			}
+		}
+	}
+	return count
+}
+
+func TestDialParams(t *testing.T) {
+	for _, dial := range []struct{ size, start int }{{100, 50}, {100, 0}, {10, 3}, {7, 6}, {1000, 999}} {
+		params := helpers.Params{"size": strconv.Itoa(dial.size), "start": strconv.Itoa(dial.start)}
+		res, err := Part2With("input_test.txt", params)
+		if err != nil {
+			t.Fatalf("Part2 failed: %v", err)
+		}
+		if expected := strconv.Itoa(clicks(t, "input_test.txt", dial.size, dial.start)); res != expected {
+			t.Errorf("size %d, start %d: got %s, want %s", dial.size, dial.start, res, expected)
+		}
+	}
+
+	if _, err := Part1With("input_test.txt", helpers.Params{"start": "100"}); err == nil {
+		t.Error("expected an error for a start outside the dial")
+	}
+}
@@ -3,12 +3,28 @@ package day02
 
 import (
 	"fmt"
+	"math/big"
 	"os"
 	"strconv"
 	"strings"
+
+	"aoc-2025/helpers/parse"
 )
 
+// An invalid ID is a block of p digits repeated k times. That is the block
+// times M = 10^(p(k-1)) + ... + 10^p + 1, so rather than looping over every
+// ID in a range we find the smallest and largest block that lands inside it
+// and sum the whole arithmetic series at once.
+
 func Part1(inputFile string) (string, error) {
+	return sumInvalid(inputFile, sumDoubled)
+}
+
+func Part2(inputFile string) (string, error) {
+	return sumInvalid(inputFile, sumPeriodic)
+}
+
+func sumInvalid(inputFile string, sum func(lo, hi *big.Int, digits int) *big.Int) (string, error) {
 	data, err := os.ReadFile(inputFile)
 	if err != nil {
 		return "", fmt.Errorf("failed to read input: %w", err)
@@ -16,62 +32,113 @@ func Part1(inputFile string) (string, error) {
 
 	parts := strings.Split(strings.TrimSpace(string(data)), ",")
 
-	acc := int64(0)
+	acc := new(big.Int)
 
 	for _, part := range parts {
-		a := strings.Split(part, "-")
-		start, _ := strconv.ParseInt(a[0], 10, 64)
-		end, _ := strconv.ParseInt(a[1], 10, 64)
-		for id := start; id <= end; id += 1 {
-			idStr := strconv.FormatInt(id, 10)
-			if len(idStr)%2 != 0 {
-				continue
-			}
-			first := idStr[:len(idStr)/2]
-			second := idStr[len(idStr)/2:]
+		start, end, err := parse.Range(part)
+		if err != nil {
+			return "", err
+		}
+		if start < 0 {
+			return "", fmt.Errorf("negative id in range %q", part)
+		}
 
-			if second == first {
-				acc += id
+		// Split the range by number of digits since M depends on it
+		for digits := len(strconv.FormatInt(start, 10)); digits <= len(strconv.FormatInt(end, 10)); digits++ {
+			lo := big.NewInt(start)
+			if low := pow10(digits - 1); lo.Cmp(low) < 0 {
+				lo = low
 			}
+			hi := big.NewInt(end)
+			if high := new(big.Int).Sub(pow10(digits), big.NewInt(1)); hi.Cmp(high) > 0 {
+				hi = high
+			}
+			acc.Add(acc, sum(lo, hi, digits))
 		}
 	}
 
-	return strconv.FormatInt(acc, 10), nil
+	return acc.String(), nil
 }
 
-func Part2(inputFile string) (string, error) {
-	data, err := os.ReadFile(inputFile)
-	if err != nil {
-		return "", fmt.Errorf("failed to read input: %w", err)
+func pow10(n int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
+
+// sumBlocks sums every number in [lo, hi] that has exactly digits digits and
+// is a block of blockLen digits repeated. lo and hi must have digits digits.
+func sumBlocks(lo, hi *big.Int, digits, blockLen int) *big.Int {
+	// M = (10^digits - 1) / (10^blockLen - 1), e.g. 1001001 for 3 x 3 digits
+	m := new(big.Int).Sub(pow10(digits), big.NewInt(1))
+	m.Quo(m, new(big.Int).Sub(pow10(blockLen), big.NewInt(1)))
+
+	// Smallest and largest block with block*M inside the range
+	first := new(big.Int).Add(lo, new(big.Int).Sub(m, big.NewInt(1)))
+	first.Quo(first, m)
+	last := new(big.Int).Quo(hi, m)
+	if first.Cmp(last) > 0 {
+		return new(big.Int)
 	}
 
-	parts := strings.Split(strings.TrimSpace(string(data)), ",")
+	// M * (first + last) * count / 2
+	count := new(big.Int).Sub(last, first)
+	count.Add(count, big.NewInt(1))
+	res := new(big.Int).Add(first, last)
+	res.Mul(res, count)
+	res.Rsh(res, 1)
+	return res.Mul(res, m)
+}
 
-	acc := int64(0)
+// sumDoubled sums the numbers made of a block repeated exactly twice
+func sumDoubled(lo, hi *big.Int, digits int) *big.Int {
+	if digits%2 != 0 {
+		return new(big.Int)
+	}
+	return sumBlocks(lo, hi, digits, digits/2)
+}
 
-	for _, part := range parts {
-		a := strings.Split(part, "-")
-		start, _ := strconv.ParseInt(a[0], 10, 64)
-		end, _ := strconv.ParseInt(a[1], 10, 64)
-		for id := start; id <= end; id += 1 {
-			idStr := strconv.FormatInt(id, 10)
-
-		patternloop:
-			for i := 1; i <= len(idStr)/2; i++ {
-				if len(idStr)%i != 0 {
-					continue
-				}
-				pattern := idStr[:i]
-				for j := i; j < len(idStr); j++ {
-					if idStr[j] != pattern[j%i] {
-						continue patternloop
-					}
-				}
-				acc += id
-				break
+// sumPeriodic sums the numbers made of a block repeated at least twice.
+//
+// A number made of blocks of length p is also made of blocks of any multiple
+// of p, so summing over every block length would count it several times.
+// Every repeated number has a block length of digits/q for some prime q, and
+// the numbers shared by several of those lengths are the ones made of blocks
+// of digits/(product of the primes). Inclusion-exclusion over the distinct
+// prime factors of digits counts each number once.
+func sumPeriodic(lo, hi *big.Int, digits int) *big.Int {
+	primes := primeFactors(digits)
+	acc := new(big.Int)
+	for subset := 1; subset < 1<<len(primes); subset++ {
+		divisor := 1
+		terms := 0
+		for i, p := range primes {
+			if subset&(1<<i) != 0 {
+				divisor *= p
+				terms++
 			}
 		}
+		s := sumBlocks(lo, hi, digits, digits/divisor)
+		if terms%2 == 1 {
+			acc.Add(acc, s)
+		} else {
+			acc.Sub(acc, s)
+		}
 	}
+	return acc
+}
 
-	return strconv.FormatInt(acc, 10), nil
+// primeFactors returns the distinct prime factors of n
+func primeFactors(n int) []int {
+	var primes []int
+	for p := 2; p*p <= n; p++ {
+		if n%p == 0 {
+			primes = append(primes, p)
+			for n%p == 0 {
+				n /= p
+			}
+		}
+	}
+	if n > 1 {
+		primes = append(primes, n)
+	}
+	return primes
 }
@@ -1,6 +1,13 @@
 package day02
 
-import "testing"
+import (
+	"math/big"
+	"strconv"
+	"strings"
+	"testing"
+
+	"aoc-2025/helpers/inputtest"
+)
 
 func TestPart1(t *testing.T) {
 	res, err := Part1("input_test.txt")
@@ -24,3 +31,113 @@ func TestPart2(t *testing.T) {
 		t.Errorf("got %s, want %s", res, expected)
 	}
 }
+
+func TestAgainstBruteForce(t *testing.T) {
+	// The digit by digit checks the arithmetic version replaced
+	doubled := func(id int64) bool {
+		s := strconv.FormatInt(id, 10)
+		return len(s)%2 == 0 && s[:len(s)/2] == s[len(s)/2:]
+	}
+
+	ranges := [][2]int64{{0, 0}, {1, 9}, {1, 100000}, {95, 115}, {998, 1012}, {123456, 987654}, {9999990, 10000010}}
+	for _, r := range ranges {
+		var want1, want2 int64
+		for id := r[0]; id <= r[1]; id++ {
+			if doubled(id) {
+				want1 += id
+			}
+			if isPeriodic(id) {
+				want2 += id
+			}
+		}
+
+		var got1, got2 big.Int
+		for digits := len(strconv.FormatInt(r[0], 10)); digits <= len(strconv.FormatInt(r[1], 10)); digits++ {
+			lo := max(r[0], pow10(digits-1).Int64())
+			hi := min(r[1], pow10(digits).Int64()-1)
+			got1.Add(&got1, sumDoubled(big.NewInt(lo), big.NewInt(hi), digits))
+			got2.Add(&got2, sumPeriodic(big.NewInt(lo), big.NewInt(hi), digits))
+		}
+		if got1.Int64() != want1 || got2.Int64() != want2 {
+			t.Errorf("%d-%d: got %s and %s, want %d and %d", r[0], r[1], &got1, &got2, want1, want2)
+		}
+	}
+}
+
+// isPeriodic is the digit by digit check for a block repeated at least twice
+func isPeriodic(id int64) bool {
+	s := strconv.FormatInt(id, 10)
+	for p := 1; p <= len(s)/2; p++ {
+		if len(s)%p == 0 && strings.Repeat(s[:p], len(s)/p) == s {
+			return true
+		}
+	}
+	return false
+}
+
+// periodicWithDigits sums every repeated number with exactly digits digits,
+// grouping them by their shortest block instead of by prime factors: the
+// numbers made of p digit blocks, minus those whose shortest block is a
+// proper divisor of p, leaves those whose shortest block is exactly p.
+func periodicWithDigits(digits int) *big.Int {
+	exact := map[int]*big.Int{}
+	total := new(big.Int)
+	for p := 1; p < digits; p++ {
+		if digits%p != 0 {
+			continue
+		}
+		// The p digit blocks 10^(p-1) .. 10^p-1 sum to (first+last)*count/2,
+		// and each is repeated by multiplying by (10^digits-1)/(10^p-1)
+		first := pow10(p - 1)
+		last := new(big.Int).Sub(pow10(p), big.NewInt(1))
+		count := new(big.Int).Sub(pow10(p), first)
+		s := new(big.Int).Add(first, last)
+		s.Mul(s, count).Rsh(s, 1)
+		s.Mul(s, new(big.Int).Sub(pow10(digits), big.NewInt(1)))
+		s.Quo(s, last)
+
+		for q, e := range exact {
+			if p%q == 0 {
+				s.Sub(s, e)
+			}
+		}
+		exact[p] = s
+		total.Add(total, s)
+	}
+	return total
+}
+
+func TestHugeRange(t *testing.T) {
+	// Check the closed form against brute force first
+	var brute int64
+	for id := int64(1); id <= 999999; id++ {
+		if isPeriodic(id) {
+			brute += id
+		}
+	}
+	closed := new(big.Int)
+	for digits := 1; digits <= 6; digits++ {
+		closed.Add(closed, periodicWithDigits(digits))
+	}
+	if !closed.IsInt64() || closed.Int64() != brute {
+		t.Fatalf("closed form up to 999999: got %s, want %d", closed, brute)
+	}
+
+	// Every repeated number with up to 18 digits, and of the 19 digit ones
+	// (19 is prime, so only repdigits) 1111111111111111111 to
+	// 8888888888888888888, which sum to 36 times the first
+	want := new(big.Int)
+	for digits := 1; digits <= 18; digits++ {
+		want.Add(want, periodicWithDigits(digits))
+	}
+	repunit := new(big.Int).Quo(new(big.Int).Sub(pow10(19), big.NewInt(1)), big.NewInt(9))
+	want.Add(want, repunit.Mul(repunit, big.NewInt(36)))
+
+	res, err := Part2(inputtest.Write(t, "1-9223372036854775807"))
+	if err != nil {
+		t.Fatalf("Part2 failed: %v", err)
+	}
+	if res != want.String() {
+		t.Errorf("got %s, want %s", res, want)
+	}
+}
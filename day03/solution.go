@@ -2,95 +2,127 @@
 package day03
 
 import (
+	"context"
 	"fmt"
+	"math/big"
 	"strconv"
-	"sync"
 
 	"aoc-2025/helpers"
 )
 
+// maxInt64Digits is the most digits that always fit in an int64
+const maxInt64Digits = 18
+
+// Part1Params and Part2Params declare the battery size, which is all that
+// differs between the parts
+var (
+	Part1Params = []helpers.Param{{Name: "k", Kind: helpers.ParamInt, Default: 2, Usage: "batteries turned on per bank"}}
+	Part2Params = []helpers.Param{{Name: "k", Kind: helpers.ParamInt, Default: 12, Usage: "batteries turned on per bank"}}
+)
+
 func Part1(inputFile string) (string, error) {
-	lines, err := helpers.ReadLines(inputFile)
+	return Part1With(inputFile, nil)
+}
+
+func Part2(inputFile string) (string, error) {
+	return Part2With(inputFile, nil)
+}
+
+func Part1With(inputFile string, params helpers.Params) (string, error) {
+	values, err := helpers.Resolve(Part1Params, params)
 	if err != nil {
-		return "", fmt.Errorf("failed to read input: %w", err)
+		return "", err
 	}
-	acc := int64(0)
-	for _, str := range lines {
-		first := 0
-		last := 0
-	digit:
-		for i, r := range str {
-			digit := int(r - '0')
-			if i < len(str)-1 {
-				if digit > first {
-					first = digit
-					last = 0
-					continue digit // Only use number once
-				}
-			}
-			if i > 0 {
-				if digit > last {
-					last = digit
-				}
-			}
-		}
-		acc += int64((first * 10) + last)
+	return sumJoltage(inputFile, values.Int("k"))
+}
+
+func Part2With(inputFile string, params helpers.Params) (string, error) {
+	values, err := helpers.Resolve(Part2Params, params)
+	if err != nil {
+		return "", err
 	}
-	return strconv.FormatInt(acc, 10), nil
+	return sumJoltage(inputFile, values.Int("k"))
 }
 
-func Part2(inputFile string) (string, error) {
+func sumJoltage(inputFile string, k int) (string, error) {
 	lines, err := helpers.ReadLines(inputFile)
 	if err != nil {
 		return "", fmt.Errorf("failed to read input: %w", err)
 	}
 
-	results := make([]int64, len(lines))
-	numWorkers := 6
-	jobs := make(chan int, len(lines))
-	var wg sync.WaitGroup
-
-	for w := 0; w < numWorkers; w++ {
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			for index := range jobs {
-				line := lines[index]
-				batterySize := 12
-				toRemove := len(line) - batterySize
-				stack := make([]byte, 0, batterySize)
-
-				for i := 0; i < len(line); i++ {
-					// While we can still remove digits and current is bigger than stack top
-					for len(stack) > 0 && toRemove > 0 && line[i] > stack[len(stack)-1] {
-						stack = stack[:len(stack)-1]
-						toRemove--
-					}
-					stack = append(stack, line[i])
-				}
-
-				// Take first k digits and convert to number
-				result := int64(0)
-				for j := 0; j < batterySize; j++ {
-					result = result*10 + int64(stack[j]-'0')
-				}
-				results[index] = result
-			}
-		}()
+	pick := func(_ context.Context, i int, line string) (Joltage, error) {
+		joltage, err := MaxSubsequence(line, k)
+		if err != nil {
+			return Joltage{}, fmt.Errorf("line %d: %w", i+1, err)
+		}
+		return joltage, nil
 	}
+	sum := func(acc helpers.Accumulator, j Joltage) helpers.Accumulator {
+		if j.Big != nil {
+			acc.AddBig(j.Big)
+		} else {
+			acc.Add(j.Value)
+		}
+		return acc
+	}
+
+	acc, err := helpers.ParallelReduce(context.Background(), lines, pick, helpers.Accumulator{}, sum)
+	if err != nil {
+		return "", err
+	}
+
+	return acc.String(), nil
+}
+
+// Joltage is a k digit number picked from a bank. Value holds it when it
+// has at most 18 digits, otherwise Big does.
+type Joltage struct {
+	Digits string
+	Value  int64
+	Big    *big.Int
+}
 
-	// Send jobs to workers
-	for i := 0; i < len(lines); i++ {
-		jobs <- i
+func (j Joltage) BigInt() *big.Int {
+	if j.Big != nil {
+		return j.Big
+	}
+	return big.NewInt(j.Value)
+}
+
+func (j Joltage) String() string {
+	return j.Digits
+}
+
+// MaxSubsequence returns the largest number that can be made by picking k
+// digits of bank in order. It keeps a stack of chosen digits and pops any
+// digit that is smaller than the next one while there are still digits to
+// spare, which is O(len(bank)).
+func MaxSubsequence(bank string, k int) (Joltage, error) {
+	if k <= 0 || k > len(bank) {
+		return Joltage{}, fmt.Errorf("can not pick %d digits from %d", k, len(bank))
 	}
-	close(jobs)
 
-	wg.Wait()
+	toRemove := len(bank) - k
+	stack := make([]byte, 0, len(bank))
 
-	acc := int64(0)
-	for _, result := range results {
-		acc += result
+	for i := 0; i < len(bank); i++ {
+		if bank[i] < '0' || bank[i] > '9' {
+			return Joltage{}, fmt.Errorf("invalid digit %q at %d", bank[i], i)
+		}
+		// While we can still remove digits and current is bigger than stack top
+		for len(stack) > 0 && toRemove > 0 && bank[i] > stack[len(stack)-1] {
+			stack = stack[:len(stack)-1]
+			toRemove--
+		}
+		stack = append(stack, bank[i])
 	}
 
-	return strconv.FormatInt(acc, 10), nil
+	// Take first k digits
+	j := Joltage{Digits: string(stack[:k])}
+	if k <= maxInt64Digits {
+		j.Value, _ = strconv.ParseInt(j.Digits, 10, 64)
+	} else {
+		j.Big, _ = new(big.Int).SetString(j.Digits, 10)
+	}
+	return j, nil
 }
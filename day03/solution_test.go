@@ -1,6 +1,10 @@
 package day03
 
-import "testing"
+import (
+	"testing"
+
+	"aoc-2025/helpers"
+)
 
 func TestPart1(t *testing.T) {
 	res, err := Part1("input_test.txt")
@@ -36,3 +40,47 @@ func TestPart2_real(t *testing.T) {
 		t.Errorf("got %s, want %s", res, expected)
 	}
 }
+
+func TestMaxSubsequence(t *testing.T) {
+	tests := []struct {
+		bank string
+		k    int
+		want string
+	}{
+		{"987654321111111", 2, "98"},
+		{"811111111111119", 2, "89"},
+		{"234234234234278", 12, "434234234278"},
+		{"1234567890123456789012345", 20, "67890123456789012345"},
+	}
+	for _, tt := range tests {
+		j, err := MaxSubsequence(tt.bank, tt.k)
+		if err != nil {
+			t.Fatalf("MaxSubsequence(%s, %d) failed: %v", tt.bank, tt.k, err)
+		}
+		if got := j.BigInt().String(); got != tt.want {
+			t.Errorf("MaxSubsequence(%s, %d): got %s, want %s", tt.bank, tt.k, got, tt.want)
+		}
+		if (tt.k > 18) != (j.Big != nil) {
+			t.Errorf("k=%d: expected big.Int only past 18 digits", tt.k)
+		}
+	}
+
+	if _, err := MaxSubsequence("12a4", 2); err == nil {
+		t.Errorf("expected error for non digit")
+	}
+}
+
+func TestBatterySizeParam(t *testing.T) {
+	// Part1 with twelve batteries is the same puzzle as Part2
+	res, err := Part1With("input_test.txt", helpers.Params{"k": "12"})
+	if err != nil {
+		t.Fatalf("Part1 failed: %v", err)
+	}
+	if expected := "3121910778619"; res != expected {
+		t.Errorf("got %s, want %s", res, expected)
+	}
+
+	if _, err := Part1With("input_test.txt", helpers.Params{"batteries": "12"}); err == nil {
+		t.Error("expected an error for an unknown parameter")
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"strconv"
 
 	"aoc-2025/helpers"
+	"aoc-2025/helpers/automaton"
 )
 
 type Pos struct {
@@ -13,9 +14,22 @@ type Pos struct {
 	Y int
 }
 
-type Cell struct {
-	N int
-	T rune
+// accessible is the automaton rule: a roll with fewer than 4 neighbouring
+// rolls can be reached by a forklift and is taken away
+func accessible(cell byte, neighbours []byte) byte {
+	if cell != '@' {
+		return cell
+	}
+	rolls := 0
+	for _, n := range neighbours {
+		if n == '@' {
+			rolls++
+		}
+	}
+	if rolls < 4 {
+		return '.'
+	}
+	return cell
 }
 
 func Part1(inputFile string) (string, error) {
@@ -23,43 +37,17 @@ func Part1(inputFile string) (string, error) {
 	if err != nil {
 		return "", fmt.Errorf("failed to read input: %w", err)
 	}
-	grid := make(map[Pos]Cell)
-	acc := int64(0)
 
-	directions := []Pos{
-		{-1, -1}, {0, -1}, {1, -1}, // top
-		{-1, 0}, {1, 0}, // same row
-		{-1, 1}, {0, 1}, {1, 1}, // bottom
-	}
-	maxY := len(lines)
-	maxX := len(lines[0])
-	for y, str := range lines {
-		for x, r := range str {
-			// update own type
-			val := grid[Pos{X: x, Y: y}]
-			val.T = r
-			grid[Pos{X: x, Y: y}] = val
-			if r == '@' {
-				for _, dir := range directions {
-					newX, newY := x+dir.X, y+dir.Y
-					if newX >= 0 && newX < maxX && newY >= 0 && newY < maxY {
-						neighbor := Pos{X: newX, Y: newY}
-						val := grid[neighbor]
-						val.N++
-						grid[neighbor] = val
-					}
-				}
-			}
-		}
-	}
-
-	for _, v := range grid {
-		if v.N < 4 && v.T == '@' {
-			acc++
-		}
-	}
+	// One generation removes exactly the rolls that are accessible now
+	grid := automaton.GridFromLines(lines, '.')
+	before := grid.Count('@')
+	automaton.Run(grid, automaton.Config[byte]{
+		Neighbourhood: automaton.Moore,
+		Rule:          accessible,
+		MaxSteps:      1,
+	})
 
-	return strconv.FormatInt(acc, 10), nil
+	return strconv.Itoa(before - grid.Count('@')), nil
 }
 
 func Part2(inputFile string) (string, error) {
@@ -67,70 +55,36 @@ func Part2(inputFile string) (string, error) {
 	if err != nil {
 		return "", fmt.Errorf("failed to read input: %w", err)
 	}
-	blankGrid := make(map[Pos]Cell)
-	removed := int64(0)
 
-	directions := []Pos{
-		{-1, -1}, {0, -1}, {1, -1}, // top
-		{-1, 0}, {1, 0}, // same row
-		{-1, 1}, {0, 1}, {1, 1}, // bottom
-	}
-	maxY := len(lines)
-	maxX := len(lines[0])
-	for y, str := range lines {
-		for x, r := range str {
-			val := blankGrid[Pos{X: x, Y: y}]
-			val.T = r
-			blankGrid[Pos{X: x, Y: y}] = val
-		}
-	}
-
-	for {
-		grid := CopyGrid(blankGrid)
-
-		for y := 0; y < maxY; y += 1 {
-			for x := 0; x < maxX; x += 1 {
-				pos := Pos{X: x, Y: y}
-				cell := grid[pos]
-				if cell.T == '@' {
-					for _, dir := range directions {
-						newX, newY := x+dir.X, y+dir.Y
-						if newX >= 0 && newX < maxX && newY >= 0 && newY < maxY {
-							neighbor := Pos{X: newX, Y: newY}
-							val := grid[neighbor]
-							val.N++
-							grid[neighbor] = val
-						}
-					}
-				}
-			}
-		}
+	removed := PeelRolls(lines)
 
-		cellRemoved := false
-		for y := 0; y < maxY; y += 1 {
-			for x := 0; x < maxX; x += 1 {
-				pos := Pos{X: x, Y: y}
-				cell := grid[pos]
-				if cell.N < 4 && cell.T == '@' {
-					removed++
-					cell.T = '.'
-					blankGrid[pos] = cell
-					cellRemoved = true
-				}
-			}
-		}
-		if !cellRemoved {
-			return strconv.FormatInt(removed, 10), nil
-		}
-	}
+	return strconv.Itoa(len(removed)), nil
 }
 
-func CopyGrid(original map[Pos]Cell) map[Pos]Cell {
-	copy := make(map[Pos]Cell, len(original))
+// Removal is a roll taken away in Part2 and the round (from 1) it went in
+type Removal struct {
+	Pos   Pos
+	Round int
+}
 
-	for pos, value := range original {
-		copy[pos] = value
-	}
+// PeelRolls keeps removing every accessible roll until none are left to
+// remove, and returns the removals in order. Each round is one synchronous
+// generation of the automaton, which only re-evaluates cells next to a roll
+// that was just removed.
+func PeelRolls(lines []string) []Removal {
+	var removed []Removal
+
+	grid := automaton.GridFromLines(lines, '.')
+	automaton.Run(grid, automaton.Config[byte]{
+		Neighbourhood: automaton.Moore,
+		Rule:          accessible,
+		OnGeneration: func(gen int, g *automaton.Grid[byte], changed []int) {
+			for _, i := range changed {
+				x, y := g.XY(i)
+				removed = append(removed, Removal{Pos: Pos{X: x, Y: y}, Round: gen})
+			}
+		},
+	})
 
-	return copy
+	return removed
 }
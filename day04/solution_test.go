@@ -24,3 +24,31 @@ func TestPart2(t *testing.T) {
 		t.Errorf("got %s, want %s", res, expected)
 	}
 }
+
+func TestPeelRollsRounds(t *testing.T) {
+	// The middle roll is surrounded, so it only goes once the corners have
+	lines := []string{
+		"@@@",
+		"@@@",
+		"@@@",
+	}
+	removed := PeelRolls(lines)
+	if len(removed) != 9 {
+		t.Fatalf("got %d removed, want 9", len(removed))
+	}
+
+	rounds := make(map[Pos]int)
+	for _, r := range removed {
+		rounds[r.Pos] = r.Round
+	}
+	want := map[Pos]int{
+		{0, 0}: 1, {2, 0}: 1, {0, 2}: 1, {2, 2}: 1,
+		{1, 0}: 2, {0, 1}: 2, {2, 1}: 2, {1, 2}: 2,
+		{1, 1}: 3,
+	}
+	for pos, round := range want {
+		if rounds[pos] != round {
+			t.Errorf("%v: got round %d, want %d", pos, rounds[pos], round)
+		}
+	}
+}
@@ -3,59 +3,37 @@ package day05
 
 import (
 	"fmt"
-	"slices"
 	"strconv"
-	"strings"
 
 	"aoc-2025/helpers"
+	"aoc-2025/helpers/parse"
 )
 
-type rangeT struct {
-	start, end int64
-}
-
 func Part1(inputFile string) (string, error) {
 	lines, err := helpers.ReadLines(inputFile)
 	if err != nil {
 		return "", fmt.Errorf("failed to read input: %w", err)
 	}
 
-	var ranges []rangeT
-	var numbers []int64
-
-	foundEmpty := false
-	for _, line := range lines {
-		if line == "" {
-			foundEmpty = true
-			continue
-		}
-
-		if !foundEmpty {
-			a := strings.Split(line, "-")
-			start, _ := strconv.ParseInt(a[0], 10, 64)
-			end, _ := strconv.ParseInt(a[1], 10, 64)
-			ranges = append(ranges, rangeT{start, end})
-		} else {
-			num, _ := strconv.ParseInt(line, 10, 64)
-			numbers = append(numbers, num)
-		}
+	fresh, rest, err := parseRanges(lines)
+	if err != nil {
+		return "", err
 	}
 
-	slices.Sort(numbers)
-
 	acc := int64(0)
 	used := make(map[int64]bool)
 
-	for _, num := range numbers {
+	for _, line := range rest {
+		num, err := strconv.ParseInt(line, 10, 64)
+		if err != nil {
+			return "", fmt.Errorf("invalid id %q: %w", line, err)
+		}
 		if used[num] {
 			continue
 		}
-		for _, r := range ranges {
-			if num >= r.start && num <= r.end {
-				acc++
-				used[num] = true
-				break
-			}
+		used[num] = true
+		if fresh.Contains(num) {
+			acc++
 		}
 	}
 
@@ -68,53 +46,34 @@ func Part2(inputFile string) (string, error) {
 		return "", fmt.Errorf("failed to read input: %w", err)
 	}
 
-	var ranges []rangeT
-
-	for _, line := range lines {
-		if line == "" {
-			break
-		}
-		a := strings.Split(line, "-")
-		start, _ := strconv.ParseInt(a[0], 10, 64)
-		end, _ := strconv.ParseInt(a[1], 10, 64)
-		ranges = append(ranges, rangeT{start, end})
+	fresh, _, err := parseRanges(lines)
+	if err != nil {
+		return "", err
 	}
 
-	slices.SortFunc(ranges, func(a, b rangeT) int {
-		if a.start < b.start {
-			return -1
-		}
-		if a.start > b.start {
-			return 1
-		}
-		return 0
-	})
+	return fresh.Covered().String(), nil
+}
 
-	var merged []rangeT
-	for _, r := range ranges {
-		if len(merged) == 0 {
-			merged = append(merged, r)
-			continue
-		}
+// parseRanges reads the "a-b" lines in the first block and returns them
+// merged, along with the lines of the second block.
+func parseRanges(lines []string) (*helpers.IntervalSet, []string, error) {
+	blocks := parse.Blocks(lines)
+	if len(blocks) == 0 {
+		return nil, nil, fmt.Errorf("no ranges found")
+	}
 
-		last := &merged[len(merged)-1]
-		// Check if current range overlaps is next to the last merged range
-		if r.start <= last.end+1 {
-			// Extend if needed
-			if r.end > last.end {
-				last.end = r.end
-			}
-		} else {
-			// No overlap, add as new range
-			merged = append(merged, r)
+	ranges := make([]helpers.Interval, 0, len(blocks[0]))
+	for _, line := range blocks[0] {
+		start, end, err := parse.Range(line)
+		if err != nil {
+			return nil, nil, err
 		}
+		ranges = append(ranges, helpers.Interval{Start: start, End: end})
 	}
 
-	// Calculate total count from merged ranges
-	acc := int64(0)
-	for _, r := range merged {
-		acc += (r.end - r.start + 1)
+	var rest []string
+	if len(blocks) > 1 {
+		rest = blocks[1]
 	}
-
-	return strconv.FormatInt(acc, 10), nil
+	return helpers.NewIntervalSet(ranges...), rest, nil
 }
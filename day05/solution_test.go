@@ -1,6 +1,10 @@
 package day05
 
-import "testing"
+import (
+	"testing"
+
+	"aoc-2025/helpers/inputtest"
+)
 
 func TestPart1(t *testing.T) {
 	res, err := Part1("input_test.txt")
@@ -24,3 +28,14 @@ func TestPart2(t *testing.T) {
 		t.Errorf("got %s, want %s", res, expected)
 	}
 }
+
+func TestPart2Overflow(t *testing.T) {
+	// 0 to MaxInt64 is one more id than an int64 can count
+	res, err := Part2(inputtest.Write(t, "0-9223372036854775807", "5-10", "", "1"))
+	if err != nil {
+		t.Fatalf("Part2 failed: %v", err)
+	}
+	if expected := "9223372036854775808"; res != expected {
+		t.Errorf("got %s, want %s", res, expected)
+	}
+}
@@ -2,137 +2,196 @@
 package day06
 
 import (
+	"errors"
 	"fmt"
-	"regexp"
+	"math"
 	"strconv"
 	"strings"
 
 	"aoc-2025/helpers"
 )
 
-type column struct {
-	size  int
-	value string
+func Part1(inputFile string) (string, error) {
+	return solve(inputFile, RowWise)
 }
 
-func Part1(inputFile string) (string, error) {
+func Part2(inputFile string) (string, error) {
+	return solve(inputFile, ColumnWise)
+}
+
+func solve(inputFile string, reading Reading) (string, error) {
 	lines, err := helpers.ReadLines(inputFile)
 	if err != nil {
 		return "", fmt.Errorf("failed to read input: %w", err)
 	}
 
-	var operations []string
-
-	lastLine := lines[len(lines)-1]
-
-	operations = append(operations, strings.Fields(lastLine)...)
-
-	parts := strings.Fields(lines[0])
+	sheet, err := ParseWorksheet(lines)
+	if err != nil {
+		return "", err
+	}
 
-	var numbers []int64
-	for _, part := range parts {
-		num, err := strconv.ParseInt(part, 10, 64)
+	var acc helpers.Accumulator
+	for _, p := range sheet.Problems {
+		res, err := p.Eval(reading)
 		if err != nil {
-			continue
+			return "", err
 		}
-		numbers = append(numbers, num)
+		acc.Add(res)
 	}
 
-	// y goes down
-	for y := 1; y < (len(lines) - 1); y++ {
-		lower := strings.Fields(lines[y])
-		// x goes to the right
-		for x, n := range lower {
-			num, _ := strconv.ParseInt(n, 10, 64)
-			res := doMath(operations[x], numbers[x], num)
+	return acc.String(), nil
+}
 
-			numbers[x] = res
-		}
-	}
+// Reading is how the digits of a problem are turned into numbers
+type Reading int
 
-	acc := int64(0)
+const (
+	// RowWise reads each row of a problem as one number, top to bottom
+	RowWise Reading = iota
+	// ColumnWise reads each column top to bottom as one number, going
+	// through the columns right to left
+	ColumnWise
+)
 
-	for _, num := range numbers {
-		acc += num
-	}
+// Problem is one block of the worksheet. Rows holds the digit rows padded to
+// the width of the block, and Line and Col are where the operator is, both
+// counting from 1.
+type Problem struct {
+	Op   string
+	Rows []string
+	Line int
+	Col  int
+}
 
-	return strconv.FormatInt(acc, 10), nil
+type Worksheet struct {
+	Problems []Problem
 }
 
-func doMath(op string, a int64, b int64) int64 {
-	switch op {
-	case "+":
-		return a + b
-	case "*":
-		return a * b
+// ParseWorksheet splits the sheet into problems. Lines may be ragged or have
+// trailing spaces trimmed, so everything is padded to the widest line. The
+// last non blank line holds the operators, and problems are separated by
+// columns that are blank in every line.
+func ParseWorksheet(lines []string) (*Worksheet, error) {
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+	if len(lines) < 2 {
+		return nil, fmt.Errorf("worksheet needs at least one row of numbers and a row of operators")
 	}
 
-	fmt.Printf("unknown operation: %s", op)
-	return 0
-}
+	width := 0
+	for _, line := range lines {
+		width = max(width, len(line))
+	}
+	grid := make([]string, len(lines))
+	for y, line := range lines {
+		grid[y] = line + strings.Repeat(" ", width-len(line))
+	}
+
+	blank := func(x int) bool {
+		for _, line := range grid {
+			if line[x] != ' ' {
+				return false
+			}
+		}
+		return true
+	}
 
-func parseColumns(line string) []column {
-	re := regexp.MustCompile(`[*+]`)
-	matches := re.FindAllStringIndex(line, -1)
+	opRow := len(grid) - 1
+	sheet := &Worksheet{}
+	for x := 0; x < width; {
+		if blank(x) {
+			x++
+			continue
+		}
+		start := x
+		for x < width && !blank(x) {
+			x++
+		}
 
-	var columns []column
-	for i, match := range matches {
-		col := column{
-			value: line[match[0]:match[1]],
+		op := strings.TrimSpace(grid[opRow][start:x])
+		opCol := start + strings.Index(grid[opRow][start:x], op) + 1
+		if op == "" {
+			return nil, fmt.Errorf("line %d, column %d: missing operator", opRow+1, start+1)
 		}
-		if i < len(matches)-1 {
-			col.size = matches[i+1][0] - match[0] - 1
-		} else {
-			col.size = len(line) - match[0]
+		if _, known := operators[op]; !known {
+			return nil, fmt.Errorf("line %d, column %d: unknown operator %q", opRow+1, opCol, op)
 		}
-		columns = append(columns, col)
+
+		p := Problem{Op: op, Line: opRow + 1, Col: opCol}
+		for _, line := range grid[:opRow] {
+			p.Rows = append(p.Rows, line[start:x])
+		}
+		sheet.Problems = append(sheet.Problems, p)
 	}
 
-	return columns
+	return sheet, nil
 }
 
-func Part2(inputFile string) (string, error) {
-	lines, err := helpers.ReadLines(inputFile)
-	if err != nil {
-		return "", fmt.Errorf("failed to read input: %w", err)
-	}
-
-	// Parse last line to get operations with their column sizes
-	lastLine := lines[len(lines)-1]
-	columns := parseColumns(lastLine)
-
-	// Lets turn it around
-	numberCols := make(map[int][]string)
-	for y := 0; y < len(lines)-1; y++ {
-		line := lines[y]
-		p := 0
-		for x := 0; x < len(columns); x++ {
-			c := columns[x]
-			column := line[p : p+c.size]
-			p += c.size + 1
-			for n := c.size - 1; n >= 0; n-- {
-				val := column[n]
-				// Create slice with c.size empty strings if it doesn't exist
-				if _, exists := numberCols[x]; !exists {
-					numberCols[x] = make([]string, c.size)
-				}
-
-				// Append to the string at index n
-				numberCols[x][n] += string(val)
+// Numbers reads the operands of the problem
+func (p Problem) Numbers(reading Reading) ([]int64, error) {
+	var fields []string
+	switch reading {
+	case RowWise:
+		fields = p.Rows
+	case ColumnWise:
+		width := len(p.Rows[0])
+		for x := width - 1; x >= 0; x-- {
+			var sb strings.Builder
+			for _, row := range p.Rows {
+				sb.WriteByte(row[x])
 			}
+			fields = append(fields, sb.String())
 		}
 	}
 
-	acc := int64(0)
-	for y := 0; y < len(columns); y++ {
-		number, _ := strconv.ParseInt(strings.TrimSpace(numberCols[y][0]), 10, 64)
-		for x := 1; x < len(numberCols[y]); x++ {
-			num, _ := strconv.ParseInt(strings.TrimSpace(numberCols[y][x]), 10, 64)
-			number = doMath(columns[y].value, number, num)
+	var numbers []int64
+	for _, f := range fields {
+		f = strings.TrimSpace(f)
+		if f == "" {
+			continue
+		}
+		num, err := strconv.ParseInt(f, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d, column %d: invalid number %q", p.Line, p.Col, f)
 		}
+		numbers = append(numbers, num)
+	}
+	if len(numbers) == 0 {
+		return nil, fmt.Errorf("line %d, column %d: problem has no numbers", p.Line, p.Col)
+	}
+	return numbers, nil
+}
 
-		acc += number
+// Eval folds the operator over the numbers from the first to the last
+func (p Problem) Eval(reading Reading) (int64, error) {
+	numbers, err := p.Numbers(reading)
+	if err != nil {
+		return 0, err
+	}
+	apply := operators[p.Op]
+	acc := numbers[0]
+	for _, num := range numbers[1:] {
+		if acc, err = apply(acc, num); err != nil {
+			return 0, fmt.Errorf("line %d, column %d: %s: %w", p.Line, p.Col, p.Op, err)
+		}
 	}
+	return acc, nil
+}
 
-	return strconv.FormatInt(acc, 10), nil
+var operators = map[string]func(a, b int64) (int64, error){
+	"+": helpers.CheckedAdd,
+	"*": helpers.CheckedMul,
+	"-": helpers.CheckedSub,
+	"/": func(a, b int64) (int64, error) {
+		if b == 0 {
+			return 0, errors.New("division by zero")
+		}
+		if a == math.MinInt64 && b == -1 {
+			return 0, helpers.ErrOverflow
+		}
+		return a / b, nil
+	},
+	"min": func(a, b int64) (int64, error) { return min(a, b), nil },
+	"max": func(a, b int64) (int64, error) { return max(a, b), nil },
 }
@@ -24,3 +24,53 @@ func TestPart2(t *testing.T) {
 		t.Errorf("got %s, want %s", res, expected)
 	}
 }
+
+func TestWorksheet(t *testing.T) {
+	// Trailing spaces trimmed and multi character operators
+	lines := []string{
+		"12   7 100",
+		" 3  15  20",
+		"-  min /",
+	}
+	sheet, err := ParseWorksheet(lines)
+	if err != nil {
+		t.Fatalf("ParseWorksheet failed: %v", err)
+	}
+
+	want := []int64{9, 7, 5}
+	for i, p := range sheet.Problems {
+		got, err := p.Eval(RowWise)
+		if err != nil {
+			t.Fatalf("problem %d failed: %v", i, err)
+		}
+		if got != want[i] {
+			t.Errorf("problem %d: got %d, want %d", i, got, want[i])
+		}
+	}
+
+	// Column wise, right to left: 23 - 1 and 75 min 1
+	want = []int64{22, 1}
+	for i, p := range sheet.Problems[:2] {
+		got, err := p.Eval(ColumnWise)
+		if err != nil || got != want[i] {
+			t.Errorf("problem %d: got %d, %v, want %d", i, got, err, want[i])
+		}
+	}
+}
+
+func TestWorksheetErrors(t *testing.T) {
+	_, err := ParseWorksheet([]string{"1 2", "+ %"})
+	if err == nil || err.Error() != `line 2, column 3: unknown operator "%"` {
+		t.Errorf("got %v", err)
+	}
+
+	sheet, _ := ParseWorksheet([]string{"9223372036854775807", "1", "+"})
+	if _, err := sheet.Problems[0].Eval(RowWise); err == nil {
+		t.Errorf("expected overflow error")
+	}
+
+	sheet, _ = ParseWorksheet([]string{"1", "0", "/"})
+	if _, err := sheet.Problems[0].Eval(RowWise); err == nil {
+		t.Errorf("expected division by zero error")
+	}
+}
@@ -8,144 +8,99 @@ import (
 	"aoc-2025/helpers"
 )
 
-type Point struct {
-	x int
-	y int
-}
-
 func Part1(inputFile string) (string, error) {
 	lines, err := helpers.ReadLines(inputFile)
 	if err != nil {
 		return "", fmt.Errorf("failed to read input: %w", err)
 	}
 
-	grid := make(map[int][]string)
-	maxY := len(lines)
-	maxX := len(lines[0])
-
-	visited := make(map[Point]bool)
-	queue := helpers.Queue[Point]{}
-	for y := 0; y < maxY; y++ {
-		line := lines[y]
-		for x := 0; x < maxX; x++ {
-			val := string(line[x])
-			if val == "S" {
-				start := Point{x: x, y: y + 1}
-				visited[start] = true
-				queue.Enqueue(start)
-				fmt.Printf("Found S %d %d", x, y)
-			}
-			grid[y] = append(grid[y], val)
-		}
+	res, err := Sweep(lines)
+	if err != nil {
+		return "", err
 	}
 
-	directions := []Point{
-		{-1, 1}, {1, 1},
+	return strconv.Itoa(res.Splits), nil
+}
+
+func Part2(inputFile string) (string, error) {
+	lines, err := helpers.ReadLines(inputFile)
+	if err != nil {
+		return "", fmt.Errorf("failed to read input: %w", err)
 	}
-	acc := int64(0)
-	for !queue.IsEmpty() {
-		curr, _ := queue.Dequeue()
-		cell := grid[curr.y][curr.x]
-		if cell == "^" {
-			// Two new beams
-			acc++
-			for _, dir := range directions {
-				newX, newY := curr.x+dir.x, curr.y
-				if newX >= 0 && newX < maxX && newY >= 0 && newY < maxY {
-					next := Point{x: newX, y: newY}
 
-					if !visited[next] {
-						visited[next] = true
-						queue.Enqueue(next)
-					}
-				}
-			}
-		} else {
-			// We are going straight
-			newX, newY := curr.x, curr.y+1
-			next := Point{x: newX, y: newY}
-			grid[curr.y][curr.x] = "|"
-			if newX >= 0 && newX < maxX && newY >= 0 && newY < maxY {
-				if !visited[next] {
-					visited[next] = true
-					queue.Enqueue(next)
-				}
-			}
-		}
+	res, err := Sweep(lines)
+	if err != nil {
+		return "", err
 	}
 
-	return strconv.FormatInt(acc, 10), nil
+	return res.Timelines.String(), nil
 }
 
-func printGrid(grid map[int][]string, maxY int) {
-	for y := 0; y <= maxY; y++ {
-		g := grid[y]
-		for _, v := range g {
-			fmt.Printf("%s", v)
-		}
-		fmt.Println("")
-	}
+type SweepResult struct {
+	// Splits is how many splitters were hit by at least one beam
+	Splits int
+	// Timelines is how many ways a single particle can reach the bottom
+	Timelines helpers.Accumulator
+	// Bottom is the number of timelines leaving each column of the last row
+	Bottom []helpers.Accumulator
 }
 
-func countPaths(grid map[int][]string, p Point, maxX, maxY int, seen map[Point]int64) int64 {
-	// Reached bottom
-	if p.y >= maxY {
-		return 1
+// Sweep follows the beam from S down the manifold one row at a time. Beams
+// only ever move down, so carrying a timeline count per column is enough:
+// a splitter sends its count to the columns left and right of it and every
+// other cell passes it straight down. Beams leaving the sides are lost.
+func Sweep(lines []string) (*SweepResult, error) {
+	maxX := 0
+	for _, line := range lines {
+		maxX = max(maxX, len(line))
 	}
 
-	// Path is invalid
-	if p.x < 0 || p.x >= maxX {
-		return 0
-	}
-
-	// Check if we have been here before
-	if val, exists := seen[p]; exists {
-		return val
-	}
-
-	// Get current cell
-	cell := grid[p.y][p.x]
-	var paths int64
-
-	if cell == "^" {
-		leftPaths := countPaths(grid, Point{x: p.x - 1, y: p.y + 1}, maxX, maxY, seen)
-		rightPaths := countPaths(grid, Point{p.x + 1, p.y + 1}, maxX, maxY, seen)
-		paths = leftPaths + rightPaths
-	} else {
-		// Go down
-		paths = countPaths(grid, Point{x: p.x, y: p.y + 1}, maxX, maxY, seen)
+	startX, startY := -1, -1
+	for y, line := range lines {
+		for x := 0; x < len(line); x++ {
+			if line[x] == 'S' {
+				startX, startY = x, y
+				break
+			}
+		}
+		if startX != -1 {
+			break
+		}
 	}
-
-	// Store in memo
-	seen[p] = paths
-	return paths
-}
-
-func Part2(inputFile string) (string, error) {
-	lines, err := helpers.ReadLines(inputFile)
-	if err != nil {
-		return "", fmt.Errorf("failed to read input: %w", err)
+	if startX == -1 {
+		return nil, fmt.Errorf("no start S found")
 	}
 
-	grid := make(map[int][]string)
-	maxY := len(lines)
-	maxX := len(lines[0])
+	res := &SweepResult{}
+	curr := make([]helpers.Accumulator, maxX)
+	next := make([]helpers.Accumulator, maxX)
+	curr[startX] = helpers.NewAccumulator(1)
 
-	var startX, startY int
-	for y := 0; y < maxY; y++ {
+	for y := startY; y < len(lines); y++ {
 		line := lines[y]
-		for x := 0; x < maxX; x++ {
-			val := string(line[x])
-			if val == "S" {
-				startX = x
-				startY = y
+		clear(next)
+		for x, c := range curr {
+			if c.IsZero() {
+				continue
+			}
+			if x < len(line) && line[x] == '^' {
+				res.Splits++
+				if x > 0 {
+					next[x-1].AddAccumulator(c)
+				}
+				if x < maxX-1 {
+					next[x+1].AddAccumulator(c)
+				}
+			} else {
+				next[x].AddAccumulator(c)
 			}
-			grid[y] = append(grid[y], val)
 		}
+		curr, next = next, curr
 	}
 
-	seen := make(map[Point]int64)
-	totalPaths := countPaths(grid, Point{x: startX, y: startY}, maxX, maxY, seen)
-
-	return strconv.FormatInt(totalPaths, 10), nil
+	res.Bottom = curr
+	for _, c := range curr {
+		res.Timelines.AddAccumulator(c)
+	}
+	return res, nil
 }
@@ -1,17 +1,21 @@
 package day07
 
-import "testing"
+import (
+	"math/big"
+	"strings"
+	"testing"
+)
 
-// func TestPart1(t *testing.T) {
-// 	res, err := Part1("input_test.txt")
-// 	if err != nil {
-// 		t.Fatalf("Part1 failed: %v", err)
-// 	}
-// 	expected := "21"
-// 	if res != expected {
-// 		t.Errorf("got %s, want %s", res, expected)
-// 	}
-// }
+func TestPart1(t *testing.T) {
+	res, err := Part1("input_test.txt")
+	if err != nil {
+		t.Fatalf("Part1 failed: %v", err)
+	}
+	expected := "21"
+	if res != expected {
+		t.Errorf("got %s, want %s", res, expected)
+	}
+}
 
 func TestPart2(t *testing.T) {
 	res, err := Part2("input_test.txt")
@@ -24,3 +28,33 @@ func TestPart2(t *testing.T) {
 		t.Errorf("got %s, want %s", res, expected)
 	}
 }
+
+func TestSweepOverflow(t *testing.T) {
+	// Every splitter row doubles the timelines, so 70 rows is 2^70
+	width := 143
+	lines := []string{strings.Repeat(".", width/2) + "S" + strings.Repeat(".", width/2)}
+	for y := 0; y < 70; y++ {
+		row := []byte(strings.Repeat(".", width))
+		for x := width/2 - y; x <= width/2+y; x += 2 {
+			row[x] = '^'
+		}
+		lines = append(lines, string(row))
+	}
+
+	res, err := Sweep(lines)
+	if err != nil {
+		t.Fatalf("Sweep failed: %v", err)
+	}
+	want := new(big.Int).Lsh(big.NewInt(1), 70)
+	if res.Timelines.Big().Cmp(want) != 0 {
+		t.Errorf("got %s, want %s", res.Timelines, want)
+	}
+
+	// The bottom row follows the binomial distribution
+	if got := res.Bottom[width/2-70].String(); got != "1" {
+		t.Errorf("got %s on the far left, want 1", got)
+	}
+	if got := res.Bottom[width/2].String(); got != "112186277816662845432" {
+		t.Errorf("got %s in the middle, want C(70, 35)", got)
+	}
+}
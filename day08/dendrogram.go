@@ -0,0 +1,135 @@
+package day08
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+
+	"aoc-2025/helpers"
+)
+
+// Merge is one join of two circuits. Nodes are numbered the way hierarchical
+// clustering tools expect: 0 to Leaves-1 are the points and the i'th merge
+// creates node Leaves+i.
+type Merge struct {
+	// Step is the pair number, from 1, that caused the merge. Pairs inside
+	// one circuit use up a step without merging anything.
+	Step     int    `json:"step"`
+	Left     int    `json:"left"`
+	Right    int    `json:"right"`
+	Pair     [2]int `json:"pair"`
+	Distance int64  `json:"distance"` // squared
+	Size     int    `json:"size"`
+}
+
+// Dendrogram is the merge history of the clusterer, in the order the merges
+// happened. Distances never decrease along it.
+type Dendrogram struct {
+	Leaves int     `json:"leaves"`
+	Merges []Merge `json:"merges"`
+}
+
+// BuildDendrogram connects every junction box in the input and returns the
+// full merge history
+func BuildDendrogram(inputFile string) (*Dendrogram, error) {
+	lines, err := helpers.ReadLines(inputFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read input: %w", err)
+	}
+
+	points, err := parsePoints(lines)
+	if err != nil {
+		return nil, err
+	}
+
+	clusterer := NewIncrementalClusterer(points)
+	clusterer.OneCluster()
+	return clusterer.History(), nil
+}
+
+// CutAtStep returns the circuits as they were after the first step pairs
+func (d *Dendrogram) CutAtStep(step int) [][]int {
+	return d.cut(func(m Merge) bool { return m.Step <= step })
+}
+
+// CutAtDistance returns the circuits formed by connecting every pair whose
+// squared distance is at most dist
+func (d *Dendrogram) CutAtDistance(dist int64) [][]int {
+	return d.cut(func(m Merge) bool { return m.Distance <= dist })
+}
+
+// cut replays merges while keep holds. The circuits are returned largest
+// first, each with its points in increasing order.
+func (d *Dendrogram) cut(keep func(Merge) bool) [][]int {
+	uf := helpers.NewUnionFind(d.Leaves)
+	for _, m := range d.Merges {
+		if !keep(m) {
+			break
+		}
+		uf.Union(m.Pair[0], m.Pair[1])
+	}
+
+	circuits := uf.Components()
+	for _, c := range circuits {
+		slices.Sort(c)
+	}
+	slices.SortFunc(circuits, func(a, b []int) int {
+		if len(a) != len(b) {
+			return len(b) - len(a)
+		}
+		return a[0] - b[0]
+	})
+	return circuits
+}
+
+// WriteJSON writes the history as indented JSON
+func (d *Dendrogram) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(d)
+}
+
+// WriteNewick writes one Newick tree per circuit, so a history that hasn't
+// reached a single circuit comes out as a forest. Leaves are labelled with
+// the point index and branch lengths are differences in squared distance.
+func (d *Dendrogram) WriteNewick(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+
+	merged := make([]bool, d.Leaves+len(d.Merges))
+	for _, m := range d.Merges {
+		merged[m.Left] = true
+		merged[m.Right] = true
+	}
+
+	var write func(node int, parentHeight int64)
+	write = func(node int, parentHeight int64) {
+		if node < d.Leaves {
+			fmt.Fprintf(bw, "%d:%d", node, parentHeight)
+			return
+		}
+		m := d.Merges[node-d.Leaves]
+		bw.WriteByte('(')
+		write(m.Left, m.Distance)
+		bw.WriteByte(',')
+		write(m.Right, m.Distance)
+		bw.WriteByte(')')
+		if parentHeight >= 0 {
+			fmt.Fprintf(bw, ":%d", parentHeight-m.Distance)
+		}
+	}
+
+	for node := range merged {
+		if merged[node] {
+			continue
+		}
+		if node < d.Leaves {
+			fmt.Fprintf(bw, "%d;\n", node)
+			continue
+		}
+		write(node, -1)
+		bw.WriteString(";\n")
+	}
+	return bw.Flush()
+}
@@ -0,0 +1,58 @@
+package day08
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestDendrogram(t *testing.T) {
+	d, err := BuildDendrogram("input_test.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(d.Merges) != d.Leaves-1 {
+		t.Fatalf("got %d merges for %d points", len(d.Merges), d.Leaves)
+	}
+	if last := d.Merges[len(d.Merges)-1]; last.Size != d.Leaves || last.Pair != [2]int{10, 12} {
+		t.Errorf("last merge %+v should join points 10 and 12 into one circuit", last)
+	}
+
+	// Same circuits as Part1 after ten pairs, without re-running
+	circuits := d.CutAtStep(10)
+	if got := len(circuits[0]) * len(circuits[1]) * len(circuits[2]); got != 40 {
+		t.Errorf("got %d, want 40", got)
+	}
+	byDistance := d.CutAtDistance(d.Merges[3].Distance)
+	if len(byDistance) != d.Leaves-4 {
+		t.Errorf("got %d circuits after four merges, want %d", len(byDistance), d.Leaves-4)
+	}
+
+	var buf bytes.Buffer
+	if err := d.WriteJSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+	var back Dendrogram
+	if err := json.Unmarshal(buf.Bytes(), &back); err != nil {
+		t.Fatal(err)
+	}
+	if back.Leaves != d.Leaves || len(back.Merges) != len(d.Merges) || back.Merges[5] != d.Merges[5] {
+		t.Error("JSON does not round trip")
+	}
+}
+
+func TestNewick(t *testing.T) {
+	// Points 0 and 1 merge at 1, point 2 joins them at 9, point 3 stays apart
+	d := &Dendrogram{Leaves: 4, Merges: []Merge{
+		{Step: 1, Left: 0, Right: 1, Pair: [2]int{0, 1}, Distance: 1, Size: 2},
+		{Step: 2, Left: 4, Right: 2, Pair: [2]int{1, 2}, Distance: 9, Size: 3},
+	}}
+	var sb strings.Builder
+	if err := d.WriteNewick(&sb); err != nil {
+		t.Fatal(err)
+	}
+	if want := "3;\n((0:1,1:1):8,2:9);\n"; sb.String() != want {
+		t.Errorf("got %q, want %q", sb.String(), want)
+	}
+}
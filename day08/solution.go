@@ -3,52 +3,26 @@ package day08
 
 import (
 	"fmt"
-	"math"
+	"io"
+	"log"
+	"os"
 	"sort"
-	"strconv"
-	"strings"
 
 	"aoc-2025/helpers"
+	"aoc-2025/helpers/geom"
+	"aoc-2025/helpers/parse"
 )
 
-type Point struct {
-	X float64
-	Y float64
-	Z float64
-}
-
-// UnionFind Trying to implement unionfind in go, gg
-type UnionFind struct {
-	parent []int
-	rank   []int
-}
-
-func (p Point) Distance(other Point) float64 {
-	dx := p.X - other.X
-	dy := p.Y - other.Y
-	dz := p.Z - other.Z
-	return math.Sqrt(dx*dx + dy*dy + dz*dz)
-}
+const debugMode = false
 
-func NewUnionFind(n int) *UnionFind {
-	uf := &UnionFind{
-		parent: make([]int, n),
-		rank:   make([]int, n),
+var l = func() *log.Logger {
+	if debugMode {
+		return log.New(os.Stdout, "", 0)
 	}
-	for i := 0; i < n; i++ {
-		uf.parent[i] = i
-		uf.rank[i] = 0
-	}
-	return uf
-}
+	return log.New(io.Discard, "", 0)
+}()
 
-// Find finds the root of the cluster containing point at x
-func (uf *UnionFind) Find(x int) int {
-	if uf.parent[x] != x {
-		uf.parent[x] = uf.Find(uf.parent[x])
-	}
-	return uf.parent[x]
-}
+type Point = geom.Vec3
 
 // Cluster Just a helper for easier printing
 type Cluster struct {
@@ -56,124 +30,126 @@ type Cluster struct {
 	Members []int
 }
 
-func (uf *UnionFind) GetClusters() []Cluster {
-	clusterMap := make(map[int][]int)
-	for i := 0; i < len(uf.parent); i++ {
-		root := uf.Find(i)
-		clusterMap[root] = append(clusterMap[root], i)
-	}
-
-	// Convert to slice
-	clusters := make([]Cluster, 0, len(clusterMap))
-	for root, members := range clusterMap {
-		clusters = append(clusters, Cluster{Root: root, Members: members})
-	}
+type PointPair struct {
+	Index1   int
+	Index2   int
+	Distance int64 // squared, so it stays exact
+}
 
-	// Sort by size (largest first)
-	sort.Slice(clusters, func(i, j int) bool {
-		return len(clusters[i].Members) > len(clusters[j].Members)
-	})
+type IncrementalClusterer struct {
+	points      []Point
+	uf          *helpers.UnionFind
+	pairs       *pairSource
+	step        int
+	step2Answer helpers.Accumulator // Yeah
 
-	return clusters
+	// node is the dendrogram node each union-find root currently stands for
+	node    []int
+	history *Dendrogram
 }
 
-// Union Merges the clusters containing x and y by finding their roots and linking them
-func (uf *UnionFind) Union(x, y int) bool {
-	rootX := uf.Find(x)
-	rootY := uf.Find(y)
-
-	if rootX == rootY {
-		return false
+func NewIncrementalClusterer(points []Point) *IncrementalClusterer {
+	node := make([]int, len(points))
+	for i := range node {
+		node[i] = i
 	}
-
-	if uf.rank[rootX] < uf.rank[rootY] {
-		uf.parent[rootX] = rootY
-	} else if uf.rank[rootX] > uf.rank[rootY] {
-		uf.parent[rootY] = rootX
-	} else {
-		uf.parent[rootY] = rootX
-		uf.rank[rootX]++
+	return &IncrementalClusterer{
+		points:  points,
+		uf:      helpers.NewUnionFind(len(points)),
+		pairs:   newPairSource(points),
+		node:    node,
+		history: &Dendrogram{Leaves: len(points)},
 	}
-	return true
 }
 
-func (uf *UnionFind) CountClusters() int {
-	// Find unique roots
-	roots := make(map[int]bool)
-	for i := 0; i < len(uf.parent); i++ {
-		roots[uf.Find(i)] = true
-	}
-	return len(roots)
+// History returns the merges made so far. It is shared with the clusterer,
+// so further steps show up in it.
+func (ic *IncrementalClusterer) History() *Dendrogram {
+	return ic.history
 }
 
-type PointPair struct {
-	Index1   int
-	Index2   int
-	Distance float64
+// pairLess orders pairs by distance, then by the lower and higher index, so
+// equal distances always come out in the same order
+func pairLess(a, b PointPair) bool {
+	if a.Distance != b.Distance {
+		return a.Distance < b.Distance
+	}
+	if a.Index1 != b.Index1 {
+		return a.Index1 < b.Index1
+	}
+	return a.Index2 < b.Index2
 }
 
-type IncrementalClusterer struct {
-	points      []Point
-	uf          *UnionFind
-	pairs       []PointPair
-	step        int
-	step2Answer float64 // Yeah
+// pairSource hands out pairs closest first without building all n² of them.
+// Every point walks its neighbours with a higher index through a k-d tree, and
+// the heap holds the nearest pair each point has not handed out yet.
+type pairSource struct {
+	neighbours []*geom.Neighbours
+	heap       *helpers.Heap[PointPair]
 }
 
-func NewIncrementalClusterer(points []Point) *IncrementalClusterer {
-	n := len(points)
-	ic := &IncrementalClusterer{
-		points: points,
-		uf:     NewUnionFind(n),
-		pairs:  make([]PointPair, 0),
-		step:   0,
+func newPairSource(points []Point) *pairSource {
+	tree := geom.NewKDTree(points)
+	ps := &pairSource{
+		neighbours: make([]*geom.Neighbours, len(points)),
+		heap:       helpers.NewHeap(pairLess),
 	}
-
-	// Calculate all pairwise distances
-	for i := 0; i < n; i++ {
-		for j := i + 1; j < n; j++ {
-			dist := points[i].Distance(points[j])
-			ic.pairs = append(ic.pairs, PointPair{
-				Index1:   i,
-				Index2:   j,
-				Distance: dist,
-			})
-		}
+	for i, p := range points {
+		ps.neighbours[i] = tree.Neighbours(p, func(j int) bool { return j <= i })
+		ps.advance(i)
 	}
+	return ps
+}
 
-	// Sort pairs by distance (closest first)
-	sort.Slice(ic.pairs, func(a, b int) bool {
-		return ic.pairs[a].Distance < ic.pairs[b].Distance
-	})
-
-	return ic
+func (ps *pairSource) advance(i int) {
+	if j, dist, ok := ps.neighbours[i].Next(); ok {
+		ps.heap.Push(PointPair{Index1: i, Index2: j, Distance: dist})
+	}
 }
 
-type Distance struct {
-	Index    int
-	Distance float64
+// Next returns the closest pair not returned yet
+func (ps *pairSource) Next() (PointPair, bool) {
+	pair, ok := ps.heap.Pop()
+	if ok {
+		ps.advance(pair.Index1)
+	}
+	return pair, ok
 }
 
 // Step performs one merge operation (combines the next closest pair)
 
 func (ic *IncrementalClusterer) Step() bool {
-	if ic.step >= len(ic.pairs) {
+	pair, ok := ic.pairs.Next()
+	if !ok {
 		return false // No more pairs to process
 	}
-
-	pair := ic.pairs[ic.step]
 	ic.step++
 
+	left := ic.node[ic.uf.Find(pair.Index1)]
+	right := ic.node[ic.uf.Find(pair.Index2)]
+
 	// Try to merge - returns true if they were in different clusters
 	merged := ic.uf.Union(pair.Index1, pair.Index2)
 
 	if merged {
 		p1 := ic.points[pair.Index1]
 		p2 := ic.points[pair.Index2]
-		fmt.Printf("Step %d: Merged point %d (%.1f,%.1f,%.1f) with point %d (%.1f,%.1f,%.1f) [distance: %.2f]\n",
+		l.Printf("Step %d: Merged point %d (%d,%d,%d) with point %d (%d,%d,%d) [distance²: %d]\n",
 			ic.step, pair.Index1, p1.X, p1.Y, p1.Z, pair.Index2, p2.X, p2.Y, p2.Z, pair.Distance)
-		ic.step2Answer = p1.X * p2.X
-		fmt.Printf("\tClusters remaining: %d\n\n", ic.uf.CountClusters())
+		ic.step2Answer = helpers.NewAccumulator(p1.X)
+		ic.step2Answer.Mul(p2.X)
+		l.Printf("\tClusters remaining: %d\n\n", ic.uf.Count())
+
+		root := ic.uf.Find(pair.Index1)
+		ic.node[root] = ic.history.Leaves + len(ic.history.Merges)
+		ic.history.Merges = append(ic.history.Merges, Merge{
+			Step:     ic.step,
+			Left:     left,
+			Right:    right,
+			Pair:     [2]int{pair.Index1, pair.Index2},
+			Distance: pair.Distance,
+			Size:     ic.uf.Size(root),
+		})
 	}
 
 	return true
@@ -187,35 +163,54 @@ func (ic *IncrementalClusterer) StepN(n int) {
 	}
 }
 
+// OneCluster merges until everything is connected. Pairs after the last
+// merge can't change anything, so they are never generated.
 func (ic *IncrementalClusterer) OneCluster() {
-	for ic.Step() {
+	for ic.uf.Count() > 1 && ic.Step() {
 	}
 }
 
+// GetCurrentClusters returns the clusters sorted by size, largest first
 func (ic *IncrementalClusterer) GetCurrentClusters() []Cluster {
-	return ic.uf.GetClusters()
+	components := ic.uf.Components()
+	clusters := make([]Cluster, 0, len(components))
+	for _, members := range components {
+		clusters = append(clusters, Cluster{Root: ic.uf.Find(members[0]), Members: members})
+	}
+
+	sort.Slice(clusters, func(i, j int) bool {
+		return len(clusters[i].Members) > len(clusters[j].Members)
+	})
+
+	return clusters
+}
+
+// Part1Params declares how many pairs to connect and how many of the largest
+// circuits to multiply. The example uses 10 pairs instead of 1000.
+var Part1Params = []helpers.Param{
+	{Name: "steps", Kind: helpers.ParamInt, Default: 1000, Usage: "closest pairs to connect"},
+	{Name: "top", Kind: helpers.ParamInt, Default: 3, Usage: "largest circuits to multiply"},
 }
 
 func Part1(inputFile string) (string, error) {
-	// different parameters for test data and real data
-	return part1Internal(inputFile, 1000, 3)
+	return Part1With(inputFile, nil)
 }
 
-func part1Internal(inputFile string, steps int, sum int) (string, error) {
+func Part1With(inputFile string, params helpers.Params) (string, error) {
+	values, err := helpers.Resolve(Part1Params, params)
+	if err != nil {
+		return "", err
+	}
+	steps, top := values.Int("steps"), values.Int("top")
+
 	lines, err := helpers.ReadLines(inputFile)
 	if err != nil {
 		return "", fmt.Errorf("failed to read input: %w", err)
 	}
 
-	var points []Point
-
-	for _, line := range lines {
-		a := strings.Split(line, ",")
-		x, _ := strconv.ParseFloat(a[0], 64)
-		y, _ := strconv.ParseFloat(a[1], 64)
-		z, _ := strconv.ParseFloat(a[2], 64)
-
-		points = append(points, Point{X: x, Y: y, Z: z})
+	points, err := parsePoints(lines)
+	if err != nil {
+		return "", err
 	}
 
 	clusterer := NewIncrementalClusterer(points)
@@ -223,24 +218,42 @@ func part1Internal(inputFile string, steps int, sum int) (string, error) {
 
 	clusters := clusterer.GetCurrentClusters()
 	printClusters(points, clusters)
-	acc := len(clusters[0].Members)
-	for s := 1; s < sum; s++ {
-		acc *= len(clusters[s].Members)
+	if top < 1 || top > len(clusters) {
+		return "", fmt.Errorf("top %d is out of range, there are %d circuits", top, len(clusters))
 	}
+	acc := helpers.NewAccumulator(1)
+	for s := 0; s < top; s++ {
+		acc.Mul(int64(len(clusters[s].Members)))
+	}
+
+	return acc.String(), nil
+}
 
-	return strconv.FormatInt(int64(acc), 10), nil
+func parsePoints(lines []string) ([]Point, error) {
+	points := make([]Point, 0, len(lines))
+	for _, line := range lines {
+		a, err := parse.CSV[int64](line, ",")
+		if err != nil {
+			return nil, err
+		}
+		if len(a) != 3 {
+			return nil, fmt.Errorf("expected x,y,z, got %q", line)
+		}
+		points = append(points, Point{X: a[0], Y: a[1], Z: a[2]})
+	}
+	return points, nil
 }
 
 func printClusters(points []Point, clusters []Cluster) {
 	for i, cluster := range clusters {
-		fmt.Printf("Cluster %d (root: %d, size: %d):\n", i+1, cluster.Root, len(cluster.Members))
+		l.Printf("Cluster %d (root: %d, size: %d):\n", i+1, cluster.Root, len(cluster.Members))
 		for _, idx := range cluster.Members {
 			p := points[idx]
-			fmt.Printf("  Point %d: (%.1f, %.1f, %.1f)\n", idx, p.X, p.Y, p.Z)
+			l.Printf("  Point %d: (%d, %d, %d)\n", idx, p.X, p.Y, p.Z)
 		}
-		fmt.Println()
+		l.Println()
 	}
-	fmt.Printf("Total clusters: %d\n", len(clusters))
+	l.Printf("Total clusters: %d\n", len(clusters))
 }
 
 func Part2(inputFile string) (string, error) {
@@ -249,24 +262,18 @@ func Part2(inputFile string) (string, error) {
 		return "", fmt.Errorf("failed to read input: %w", err)
 	}
 
-	var points []Point
-
-	for _, line := range lines {
-		a := strings.Split(line, ",")
-		x, _ := strconv.ParseFloat(a[0], 64)
-		y, _ := strconv.ParseFloat(a[1], 64)
-		z, _ := strconv.ParseFloat(a[2], 64)
-
-		points = append(points, Point{X: x, Y: y, Z: z})
+	points, err := parsePoints(lines)
+	if err != nil {
+		return "", err
 	}
 
 	clusterer := NewIncrementalClusterer(points)
 	clusterer.OneCluster()
 
 	// Show current clusters
-	fmt.Println("=== Current cluster state ===")
+	l.Println("=== Current cluster state ===")
 	clusters := clusterer.GetCurrentClusters()
 	printClusters(points, clusters)
 
-	return strconv.FormatInt(int64(clusterer.step2Answer), 10), nil
+	return clusterer.step2Answer.String(), nil
 }
@@ -1,15 +1,31 @@
 package day08
 
-import "testing"
+import (
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"aoc-2025/helpers"
+	"aoc-2025/helpers/inputtest"
+)
 
 func TestPart1(t *testing.T) {
-	res, err := part1Internal("input_test.txt", 10, 3)
-	if err != nil {
-		t.Fatalf("Part1 failed: %v", err)
+	// The example only has 20 boxes, so it connects fewer pairs
+	tests := []struct {
+		params   helpers.Params
+		expected string
+	}{
+		{helpers.Params{"steps": "10"}, "40"},
+		{helpers.Params{"steps": "10", "top": "1"}, "5"},
 	}
-	expected := "40"
-	if res != expected {
-		t.Errorf("got %s, want %s", res, expected)
+	for _, tt := range tests {
+		res, err := Part1With("input_test.txt", tt.params)
+		if err != nil {
+			t.Fatalf("Part1 failed: %v", err)
+		}
+		if res != tt.expected {
+			t.Errorf("%v: got %s, want %s", tt.params, res, tt.expected)
+		}
 	}
 }
 
@@ -24,3 +40,47 @@ func TestPart2(t *testing.T) {
 		t.Errorf("got %s, want %s", res, expected)
 	}
 }
+
+func TestPairOrder(t *testing.T) {
+	// Small coordinates so there are plenty of equal distances
+	rng := rand.New(rand.NewPCG(8, 8))
+	points := make([]Point, 300)
+	for i := range points {
+		points[i] = Point{X: rng.Int64N(10), Y: rng.Int64N(10), Z: rng.Int64N(10)}
+	}
+
+	var want []PointPair
+	for i := range points {
+		for j := i + 1; j < len(points); j++ {
+			want = append(want, PointPair{Index1: i, Index2: j, Distance: points[i].DistSq(points[j])})
+		}
+	}
+	slices.SortFunc(want, func(a, b PointPair) int {
+		if pairLess(a, b) {
+			return -1
+		}
+		return 1
+	})
+
+	ps := newPairSource(points)
+	for n, w := range want {
+		got, ok := ps.Next()
+		if !ok || got != w {
+			t.Fatalf("pair %d: got %+v, want %+v", n, got, w)
+		}
+	}
+	if _, ok := ps.Next(); ok {
+		t.Errorf("expected no pairs after %d", len(want))
+	}
+}
+
+func TestPart2Overflow(t *testing.T) {
+	// The last pair joined has X coordinates whose product is just over 2*10^19
+	res, err := Part2(inputtest.Write(t, "4000000000,0,0", "5000000000,0,0", "4000000001,0,0"))
+	if err != nil {
+		t.Fatalf("Part2 failed: %v", err)
+	}
+	if expected := "20000000005000000000"; res != expected {
+		t.Errorf("got %s, want %s", res, expected)
+	}
+}
@@ -3,47 +3,15 @@ package day09
 
 import (
 	"fmt"
-	"math"
-	"sort"
-	"strconv"
-	"strings"
+	"math/big"
+	"time"
 
 	"aoc-2025/helpers"
+	"aoc-2025/helpers/geom"
+	"aoc-2025/helpers/parse"
 )
 
-type Point struct {
-	X int64
-	Y int64
-}
-
-type PointPair struct {
-	p1   Point
-	p2   Point
-	area int64
-}
-
-type Range struct {
-	min int64
-	max int64
-}
-
-func (p1 Point) Area(p2 Point) int64 {
-	xDiff := int64(0)
-	if p1.X > p2.X {
-		xDiff = p1.X - p2.X
-	} else {
-		xDiff = p2.X - p1.X
-	}
-
-	yDiff := int64(0)
-	if p1.Y > p2.Y {
-		yDiff = p1.Y - p2.Y
-	} else {
-		yDiff = p2.Y - p1.Y
-	}
-
-	return (xDiff + 1) * (yDiff + 1)
-}
+type Point = geom.Vec2
 
 func Part1(inputFile string) (string, error) {
 	lines, err := helpers.ReadLines(inputFile)
@@ -51,296 +19,99 @@ func Part1(inputFile string) (string, error) {
 		return "", fmt.Errorf("failed to read input: %w", err)
 	}
 
-	var points []Point
-
-	for _, line := range lines {
-		a := strings.Split(line, ",")
-		x, _ := strconv.ParseInt(a[0], 10, 64)
-		y, _ := strconv.ParseInt(a[1], 10, 64)
-		point := Point{X: x, Y: y}
-
-		points = append(points, point)
+	points, err := parsePoints(lines)
+	if err != nil {
+		return "", err
 	}
 
-	biggestArea := int64(0)
+	// Same as Part2, an area can need 128 bits
+	biggestArea := new(big.Int)
 	for i, p1 := range points {
-		for j, p2 := range points {
-			if j == i {
-				continue
-			}
-
-			area := p1.Area(p2)
-			if area > biggestArea {
+		for _, p2 := range points[i+1:] {
+			if area := rectArea(p1, p2); area.Cmp(biggestArea) > 0 {
 				biggestArea = area
 			}
 		}
 	}
 
-	return strconv.FormatInt(biggestArea, 10), nil
+	return biggestArea.String(), nil
 }
 
 func Part2(inputFile string) (string, error) {
-	lines, err := helpers.ReadLines(inputFile)
+	answer, err := Part2Answer(inputFile)
 	if err != nil {
-		return "", fmt.Errorf("failed to read input: %w", err)
-	}
-
-	var redpoints []Point
-	completeGrid := make(map[Point]string)
-	redpointsY := make(map[int64][]Point)
-	redpointsX := make(map[int64][]Point)
-
-	maxX := int64(0)
-	maxY := int64(0)
-
-	for _, line := range lines {
-		a := strings.Split(line, ",")
-		x, _ := strconv.ParseInt(a[0], 10, 64)
-		y, _ := strconv.ParseInt(a[1], 10, 64)
-		if maxX < x {
-			maxX = x
-		}
-		if maxY < y {
-			maxY = y
-		}
-
-		point := Point{X: x, Y: y}
-
-		redpointsY[y] = append(redpointsY[y], point)
-		redpointsX[x] = append(redpointsX[x], point)
-		completeGrid[point] = "r"
-		redpoints = append(redpoints, point)
+		return "", err
 	}
+	return answer.String(), nil
+}
 
-	max := int64(math.Max(float64(maxX), float64(maxY)))
-
-	greenpointsY := make(map[int64][]Point)
-	greenpointsX := make(map[int64][]Point)
-	for i := int64(0); i <= max; i++ {
-		// First add greenpoints on x axis
-		if xpoints, exists := redpointsX[i]; exists {
-			lowY := findLowestY(xpoints).Y
-			highY := findHighestY(xpoints).Y
-
-			for y := lowY + 1; y < highY; y++ {
-				greenpoint := Point{X: i, Y: y}
-				greenpointsX[i] = append(greenpointsX[i], greenpoint)
-				greenpointsY[y] = append(greenpointsY[y], greenpoint)
-				completeGrid[greenpoint] = "g"
-			}
-		}
-		if ypoints, exists := redpointsY[i]; exists {
-			lowX := findLowestX(ypoints).X
-			highX := findHighestX(ypoints).X
-
-			for x := lowX + 1; x < highX; x++ {
-				greenpoint := Point{X: x, Y: i}
-				greenpointsX[x] = append(greenpointsX[x], greenpoint)
-				greenpointsY[i] = append(greenpointsY[i], greenpoint)
-				completeGrid[greenpoint] = "g"
-			}
-		}
-		// Then add greenpoints on y axis
+// Part2Answer also reports which corners the largest rectangle uses
+func Part2Answer(inputFile string) (helpers.Answer, error) {
+	lines, err := helpers.ReadLines(inputFile)
+	if err != nil {
+		return helpers.Answer{}, fmt.Errorf("failed to read input: %w", err)
 	}
 
-	// Then draw green ones inside green
-	for i := int64(0); i <= max; i++ {
-		if xpoints, exists := greenpointsX[i]; exists {
-			lowY := findLowestY(xpoints).Y
-			highY := findHighestY(xpoints).Y
-
-			for y := lowY + 1; y < highY; y++ {
-				greenpoint := Point{X: i, Y: y}
-				if _, exists := completeGrid[greenpoint]; !exists {
-					greenpointsX[i] = append(greenpointsX[i], greenpoint)
-					greenpointsY[y] = append(greenpointsY[y], greenpoint)
-					completeGrid[greenpoint] = "g"
-				}
-			}
-		}
-		if ypoints, exists := greenpointsY[i]; exists {
-			lowX := findLowestX(ypoints).X
-			highX := findHighestX(ypoints).X
-
-			for x := lowX + 1; x < highX; x++ {
-				greenpoint := Point{X: x, Y: i}
-				if _, exists := completeGrid[greenpoint]; !exists {
-					greenpointsX[x] = append(greenpointsX[x], greenpoint)
-					greenpointsY[i] = append(greenpointsY[i], greenpoint)
-					completeGrid[greenpoint] = "g"
-				}
-			}
-		}
+	points, err := parsePoints(lines)
+	if err != nil {
+		return helpers.Answer{}, err
 	}
 
-	// printGrid(completeGrid, maxY, maxX)
-
-	// Pre-compute filled ranges for fast boundary checking
-	fmt.Println("Computing filled ranges for optimization")
-	filledRangeX := make(map[int64]Range)
-	filledRangeY := make(map[int64]Range)
-
-	for p := range completeGrid {
-		// Track min/max Y for each X
-		if r, exists := filledRangeX[p.X]; exists {
-			if p.Y < r.min {
-				filledRangeX[p.X] = Range{min: p.Y, max: r.max}
-			}
-			if p.Y > r.max {
-				filledRangeX[p.X] = Range{min: r.min, max: p.Y}
-			}
-		} else {
-			filledRangeX[p.X] = Range{min: p.Y, max: p.Y}
-		}
-
-		// Track min/max X for each Y
-		if r, exists := filledRangeY[p.Y]; exists {
-			if p.X < r.min {
-				filledRangeY[p.Y] = Range{min: p.X, max: r.max}
-			}
-			if p.X > r.max {
-				filledRangeY[p.Y] = Range{min: r.min, max: p.X}
-			}
-		} else {
-			filledRangeY[p.Y] = Range{min: p.X, max: p.X}
-		}
+	// The red tiles are the corners of the loop, and the green tiles are the
+	// rest of the loop and everything it encloses
+	start := time.Now()
+	floor, err := geom.NewRectilinear(points)
+	if err != nil {
+		return helpers.Answer{}, err
 	}
+	built := time.Since(start)
 
-	// Generate all point pairs and sort by area (descending)
-	var pairs []PointPair
-	for i, p1 := range redpoints {
-		for j, p2 := range redpoints {
-			if j <= i {
+	// Coordinates can be anywhere in int64, so an area can need 128 bits
+	start = time.Now()
+	biggestArea := new(big.Int)
+	var corners []Point
+	for i, p1 := range points {
+		for _, p2 := range points[i+1:] {
+			if !floor.ContainsRect(geom.RectFrom(p1, p2)) {
 				continue
 			}
-			area := p1.Area(p2)
-			pairs = append(pairs, PointPair{p1: p1, p2: p2, area: area})
-		}
-	}
-
-	// Sort pairs by area in descending order
-	sort.Slice(pairs, func(i, j int) bool {
-		return pairs[i].area > pairs[j].area
-	})
-
-	// Check largest rectangles first, with early termination
-	biggestArea := int64(0)
-	for _, pair := range pairs {
-		// Early termination - if remaining pairs can't beat current best, stop
-		if pair.area <= biggestArea {
-			break
-		}
-		if isRectangleFilled(pair.p1, pair.p2, filledRangeX, filledRangeY) {
-			biggestArea = pair.area
-		}
-	}
-
-	return strconv.FormatInt(biggestArea, 10), nil
-}
-
-func isRectangleFilled(p1, p2 Point, filledRangeX, filledRangeY map[int64]Range) bool {
-	minX := p1.X
-	maxX := p2.X
-	if p2.X < p1.X {
-		minX = p2.X
-		maxX = p1.X
-	}
-
-	minY := p1.Y
-	maxY := p2.Y
-	if p2.Y < p1.Y {
-		minY = p2.Y
-		maxY = p1.Y
-	}
-
-	// Boundary check: for each X, verify filled range covers [minY, maxY]
-	for x := minX; x <= maxX; x++ {
-		r, exists := filledRangeX[x]
-		if !exists || r.min > minY || r.max < maxY {
-			return false
-		}
-	}
-
-	// Boundary check: for each Y, verify filled range covers [minX, maxX]
-	for y := minY; y <= maxY; y++ {
-		r, exists := filledRangeY[y]
-		if !exists || r.min > minX || r.max < maxX {
-			return false
-		}
-	}
-
-	return true
-}
-
-func printGrid(grid map[Point]string, maxY, maxX int64) {
-	if len(grid) == 0 {
-		return
-	}
-	for y := int64(0); y <= maxY; y++ {
-		fmt.Printf("%d ", y)
-		for x := int64(0); x <= maxX; x++ {
-			if val, ok := grid[Point{x, y}]; ok {
-				fmt.Print(val)
-			} else {
-				fmt.Print(".")
+			if area := rectArea(p1, p2); area.Cmp(biggestArea) > 0 {
+				biggestArea = area
+				corners = []Point{p1, p2}
 			}
 		}
-		fmt.Println()
-	}
-}
-
-func findHighestY(points []Point) Point {
-	if len(points) == 0 {
-		panic("empty points list")
 	}
 
-	highPoint := points[0]
-	for _, p := range points[1:] {
-		if p.Y > highPoint.Y {
-			highPoint = p
-		}
+	answer := helpers.BigAnswer(biggestArea)
+	for i, c := range corners {
+		answer = answer.With(fmt.Sprintf("corner %d", i+1), fmt.Sprintf("%d,%d", c.X, c.Y))
 	}
-	return highPoint
+	return answer.
+		Timed("build floor", built).
+		Timed("search", time.Since(start)), nil
 }
 
-func findLowestY(points []Point) Point {
-	if len(points) == 0 {
-		panic("empty points list")
-	}
-
-	minPoint := points[0]
-	for _, p := range points[1:] {
-		if p.Y < minPoint.Y {
-			minPoint = p
-		}
+// rectArea counts the tiles in the rectangle with p1 and p2 as corners
+func rectArea(p1, p2 Point) *big.Int {
+	r := geom.RectFrom(p1, p2)
+	side := func(lo, hi int64) *big.Int {
+		n := new(big.Int).Sub(big.NewInt(hi), big.NewInt(lo))
+		return n.Add(n, big.NewInt(1))
 	}
-	return minPoint
+	return new(big.Int).Mul(side(r.Min.X, r.Max.X), side(r.Min.Y, r.Max.Y))
 }
 
-func findHighestX(points []Point) Point {
-	if len(points) == 0 {
-		panic("empty points list")
-	}
-
-	highPoint := points[0]
-	for _, p := range points[1:] {
-		if p.X > highPoint.X {
-			highPoint = p
+func parsePoints(lines []string) ([]Point, error) {
+	points := make([]Point, 0, len(lines))
+	for _, line := range lines {
+		a, err := parse.CSV[int64](line, ",")
+		if err != nil {
+			return nil, err
 		}
-	}
-	return highPoint
-}
-
-func findLowestX(points []Point) Point {
-	if len(points) == 0 {
-		panic("empty points list")
-	}
-
-	minPoint := points[0]
-	for _, p := range points[1:] {
-		if p.X < minPoint.X {
-			minPoint = p
+		if len(a) != 2 {
+			return nil, fmt.Errorf("expected x,y, got %q", line)
 		}
+		points = append(points, Point{X: a[0], Y: a[1]})
 	}
-	return minPoint
+	return points, nil
 }
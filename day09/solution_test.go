@@ -1,6 +1,12 @@
 package day09
 
-import "testing"
+import (
+	"errors"
+	"testing"
+
+	"aoc-2025/helpers/geom"
+	"aoc-2025/helpers/inputtest"
+)
 
 func TestPart1(t *testing.T) {
 	res, err := Part1("input_test.txt")
@@ -24,3 +30,94 @@ func TestPart2(t *testing.T) {
 		t.Errorf("got %s, want %s", res, expected)
 	}
 }
+
+// int64Corners is an L shape spanning the whole int64 plane, which is 2^64
+// tiles wide, so areas need 128 bits
+var int64Corners = []string{
+	"-9223372036854775808,-9223372036854775808",
+	"9223372036854775807,-9223372036854775808",
+	"9223372036854775807,9223372036854775807",
+	"0,9223372036854775807",
+	"0,0",
+	"-9223372036854775808,0",
+}
+
+func TestPart1Int64Corners(t *testing.T) {
+	res, err := Part1(inputtest.Write(t, int64Corners...))
+	if err != nil {
+		t.Fatalf("Part1 failed: %v", err)
+	}
+
+	// Opposite corners of the plane: 2^64 by 2^64
+	expected := "340282366920938463463374607431768211456"
+	if res != expected {
+		t.Errorf("got %s, want %s", res, expected)
+	}
+}
+
+func TestPart2Int64Corners(t *testing.T) {
+	input := inputtest.Write(t, int64Corners...)
+
+	res, err := Part2(input)
+	if err != nil {
+		t.Fatalf("Part2 failed: %v", err)
+	}
+
+	// The bottom half of the L: 2^64 wide and 2^63+1 high
+	expected := "170141183460469231750134047789593657344"
+	if res != expected {
+		t.Errorf("got %s, want %s", res, expected)
+	}
+}
+
+func TestPart2Concave(t *testing.T) {
+	// A 21x21 square with an 11x11 hole in the middle, opened to the outside
+	// by a channel at the bottom. Every row and column through the hole has
+	// green tiles on both sides of it, which fooled the old row and column
+	// extent check into accepting the whole square (441).
+	input := inputtest.Write(t,
+		"0,0", "8,0", "8,5", "5,5", "5,15", "15,15",
+		"15,5", "12,5", "12,0", "20,0", "20,20", "0,20",
+	)
+
+	res, err := Part2(input)
+	if err != nil {
+		t.Fatalf("Part2 failed: %v", err)
+	}
+
+	// Any one side of the ring, 6 tiles wide and 16 long
+	expected := "96"
+	if res != expected {
+		t.Errorf("got %s, want %s", res, expected)
+	}
+}
+
+func TestPart2Invalid(t *testing.T) {
+	diagonal := inputtest.Write(t, "0,0", "4,0", "4,4", "1,4", "0,3")
+	if _, err := Part2(diagonal); !errors.Is(err, geom.ErrNotRectilinear) {
+		t.Errorf("got %v, want %v", err, geom.ErrNotRectilinear)
+	}
+
+	crossing := inputtest.Write(t, "0,0", "4,0", "4,4", "2,4", "2,-2", "0,-2")
+	if _, err := Part2(crossing); !errors.Is(err, geom.ErrSelfIntersecting) {
+		t.Errorf("got %v, want %v", err, geom.ErrSelfIntersecting)
+	}
+}
+
+func TestPart2Answer(t *testing.T) {
+	answer, err := Part2Answer("input_test.txt")
+	if err != nil {
+		t.Fatalf("Part2 failed: %v", err)
+	}
+	if n, ok := answer.Int64(); !ok || n != 24 {
+		t.Errorf("got %s, want 24", answer)
+	}
+
+	details := map[string]string{}
+	for _, d := range answer.Details {
+		details[d.Key] = d.Value
+	}
+	if details["corner 1"] != "9,5" || details["corner 2"] != "2,3" {
+		t.Errorf("got corners %v, want 9,5 and 2,3", details)
+	}
+}
@@ -5,29 +5,39 @@ import (
 	"fmt"
 	"io"
 	"log"
-	"math"
 	"os"
+	"slices"
 	"strconv"
 	"strings"
 
 	"aoc-2025/helpers"
+	"aoc-2025/helpers/gf2"
+	"aoc-2025/helpers/linalg"
+	"aoc-2025/helpers/parse"
 )
 
-func Part1(inputFile string) (string, error) {
-	// DEBUG MODE: Set to true for detailed logging, false for minimal output
-	const debugMode = true
+const debugMode = false
 
-	// Create a logger that either logs to stdout or discards output
-	var l *log.Logger
+var l = func() *log.Logger {
 	if debugMode {
-		l = log.New(os.Stdout, "", 0) // Log to stdout with no prefix/timestamp
-	} else {
-		l = log.New(io.Discard, "", 0) // Discard all output
+		return log.New(os.Stdout, "", 0)
 	}
+	return log.New(io.Discard, "", 0)
+}()
 
+func Part1(inputFile string) (string, error) {
+	answer, err := Part1Answer(inputFile)
+	if err != nil {
+		return "", err
+	}
+	return answer.String(), nil
+}
+
+// Part1Answer also reports which buttons were pressed on each machine
+func Part1Answer(inputFile string) (helpers.Answer, error) {
 	lines, err := helpers.ReadLines(inputFile)
 	if err != nil {
-		return "", fmt.Errorf("failed to read input: %w", err)
+		return helpers.Answer{}, fmt.Errorf("failed to read input: %w", err)
 	}
 
 	// XOR LOGIC OVERVIEW:
@@ -40,147 +50,60 @@ func Part1(inputFile string) (string, error) {
 	// Example: Target = 101 (binary)
 	//   Button A = 001, Button B = 100
 	//   A ^ B = 001 ^ 100 = 101 ✓ (matches target with 2 buttons)
+	//
+	// Pressing a button twice cancels out, so this is a linear system over
+	// GF(2) with one equation per light and one variable per button.
 
-	totalCost := 0
+	var totalCost helpers.Accumulator
+	var details []helpers.Detail
 	for lineNum, li := range lines {
-		line, _ := parseLine(li)
+		line, err := parseLine(li)
+		if err != nil {
+			return helpers.Answer{}, fmt.Errorf("line %d: %w", lineNum+1, err)
+		}
 
-		l.Printf("\n=== Line %d ===\n", lineNum+1)
-		l.Printf("Pattern: %s\n", line.Pattern)
-		l.Printf("Target N (binary): %b (decimal: %d)\n", line.N, line.N)
-		l.Printf("Available buttons: %d\n", len(line.Buttons))
-		for i, btn := range line.Buttons {
-			l.Printf("  Button %d: %b (decimal: %d)\n", i, btn, btn)
-		}
-
-		numButtons := len(line.Buttons)
-		minButtonsNeeded := math.MaxInt64 // Start with impossible value
-
-		// Try all possible combinations of buttons (2^n combinations)
-		// This is brute force: test every subset of buttons to see which XORs to target
-		//
-		// HOW THE LOOP WORKS:
-		// We use 'combination' as a bitmask to represent which buttons to press
-		// Example with 3 buttons (A, B, C):
-		//   combination = 0 (binary: 000) → press no buttons
-		//   combination = 1 (binary: 001) → press button 0 (A)
-		//   combination = 2 (binary: 010) → press button 1 (B)
-		//   combination = 3 (binary: 011) → press buttons 0,1 (A,B)
-		//   combination = 4 (binary: 100) → press button 2 (C)
-		//   combination = 5 (binary: 101) → press buttons 0,2 (A,C)
-		//   combination = 6 (binary: 110) → press buttons 1,2 (B,C)
-		//   combination = 7 (binary: 111) → press buttons 0,1,2 (A,B,C)
-		totalCombinations := 1 << numButtons
-		l.Printf("\nTrying %d combinations...\n", totalCombinations)
-
-		for combination := range totalCombinations {
-			// OPTIMIZATION: Count how many buttons this combination uses
-			// We can skip this combination if it uses more buttons than our current best
-			numButtonsInCombo := 0
-			for bitPos := range numButtons {
-				if (combination>>bitPos)%2 == 1 {
-					numButtonsInCombo++
-				}
-			}
+		pressed, err := minPresses(line)
+		if err != nil {
+			return helpers.Answer{}, fmt.Errorf("line %d: %w", lineNum+1, err)
+		}
 
-			// Early termination: skip if this combination uses too many buttons
-			if numButtonsInCombo >= minButtonsNeeded {
-				l.Printf("\n  Combination %d: Skipping (uses %d buttons, already found solution with %d)\n",
-					combination, numButtonsInCombo, minButtonsNeeded)
-				continue
-			}
+		buttons := slices.Collect(pressed.Bits())
+		l.Printf("Line %d: pattern %s, press buttons %v\n", lineNum+1, line.Pattern, buttons)
+		details = append(details, helpers.Detail{Key: fmt.Sprintf("machine %d", lineNum+1), Value: fmt.Sprint(buttons)})
+		totalCost.Add(int64(pressed.Count()))
+	}
 
-			// XOR starts at 0 (all bits off)
-			// As we "press" buttons, we XOR their bit patterns into the result
-			xorResult := 0
-			buttonsPressed := 0
-			selectedButtons := []int{}
-
-			l.Printf("\n  Combination %d (binary: %0*b):\n", combination, numButtons, combination)
-
-			// INNER LOOP: Check each bit position in 'combination' to see which buttons are selected
-			// The 'combination' number itself is a bitmask: if bit i is set, button i is pressed
-			//
-			// Example: combination = 5 (binary: 101), numButtons = 3
-			//   bitPos = 0: (5 >> 0) % 2 = 5 % 2 = 1 → button 0 is pressed ✓
-			//   bitPos = 1: (5 >> 1) % 2 = 2 % 2 = 0 → button 1 is NOT pressed ✗
-			//   bitPos = 2: (5 >> 2) % 2 = 1 % 2 = 1 → button 2 is pressed ✓
-			for bitPos := range numButtons {
-				// Right shift 'combination' by bitPos positions, then check if lowest bit is 1
-				// This extracts the bit at position bitPos
-				//
-				// DETAILED BREAKDOWN:
-				// 1. combination >> bitPos : shift right to move bit bitPos to position 0
-				// 2. result % 2 : get the lowest bit (0 or 1)
-				// 3. == 1 : check if the bit is set
-				bitIsSet := (combination >> bitPos) % 2
-
-				if bitIsSet == 1 {
-					l.Printf("    Button %d is PRESSED (bit %d is set in combination %d)\n", bitPos, bitPos, combination)
-					l.Printf("      Before XOR: xorResult = %b (decimal: %d)\n", xorResult, xorResult)
-					l.Printf("      Button %d value: %b (decimal: %d)\n", bitPos, line.Buttons[bitPos], line.Buttons[bitPos])
-
-					// XOR OPERATION: Toggle the bits that this button affects
-					// Example: If xorResult = 0101 and button = 0011
-					//          then xorResult ^ button = 0101 ^ 0011 = 0110
-					//
-					// XOR truth table for each bit position:
-					//   0 ^ 0 = 0  (both off → stays off)
-					//   0 ^ 1 = 1  (one on → turns on)
-					//   1 ^ 0 = 1  (one on → stays on)
-					//   1 ^ 1 = 0  (both on → cancels out, turns off)
-					//
-					// XOR properties:
-					//   - Same bit: 0^0=0, 1^1=0 (cancels out)
-					//   - Different bit: 0^1=1, 1^0=1 (toggles on)
-					//   - Associative: (A^B)^C = A^(B^C) so order doesn't matter
-					xorResult ^= line.Buttons[bitPos]
-
-					l.Printf("      After XOR:  xorResult = %b (decimal: %d)\n", xorResult, xorResult)
-
-					buttonsPressed++
-					selectedButtons = append(selectedButtons, bitPos)
-				} else {
-					l.Printf("    Button %d is NOT pressed (bit %d is 0 in combination %d)\n", bitPos, bitPos, combination)
-				}
-			}
+	answer := totalCost.Answer()
+	answer.Details = details
+	return answer, nil
+}
 
-			// Check if the XOR of all selected buttons equals our target pattern
-			//
-			// Example: Target = 111, Buttons: [001, 010, 100]
-			//   After button 0: 001 ≠ 111 (not done yet!)
-			//   After button 1: 001 ^ 010 = 011 ≠ 111 (not done yet!)
-			//   After button 2: 011 ^ 100 = 111 ✓ (NOW we can check!)
-			l.Printf("    Final XOR result: %b, Target: %b\n", xorResult, line.N)
-			if xorResult == line.N {
-				l.Printf("  ✓✓✓ SUCCESS! Combination %d: buttons %v → XOR result = %b (pressed: %d buttons)\n",
-					combination, selectedButtons, xorResult, buttonsPressed)
-				l.Printf("      This is now our best solution! (previous best: %d buttons)\n", minButtonsNeeded)
-				minButtonsNeeded = min(minButtonsNeeded, buttonsPressed)
-			} else {
-				l.Printf("  ✗ No match (result %b ≠ target %b)\n", xorResult, line.N)
+// minPresses returns the smallest set of buttons that toggles exactly the
+// lights in the target pattern.
+func minPresses(line ParsedLine) (*helpers.BitSet, error) {
+	system := gf2.NewSystem(len(line.Buttons))
+	for light := range line.N.Len() {
+		coeffs := helpers.NewBitSet(len(line.Buttons))
+		for i, btn := range line.Buttons {
+			if btn.Test(light) {
+				coeffs.Set(i)
 			}
 		}
-
-		if minButtonsNeeded <= numButtons {
-			l.Printf("→ Minimum buttons needed: %d\n", minButtonsNeeded)
-			totalCost += minButtonsNeeded
-		} else {
-			l.Printf("→ No valid combination found!\n")
-		}
-		l.Printf("Running total: %d\n", totalCost)
+		system.AddEquation(coeffs, line.N.Test(light))
 	}
 
-	fmt.Printf("\n=== FINAL RESULT ===\n")
-	fmt.Printf("Total cost: %d\n", totalCost)
-
-	return strconv.Itoa(totalCost), nil
+	sol, err := system.Solve()
+	if err != nil {
+		return nil, fmt.Errorf("machine %s can not be configured: %w", line.Pattern, err)
+	}
+	return sol.MinWeight()
 }
 
 type ParsedLine struct {
-	N       int
+	N       *helpers.BitSet
 	Pattern string
-	Buttons []int
+	Buttons []*helpers.BitSet
+	Joltage []int64 // counter targets from the {...} section, used in part 2
 }
 
 func parseLine(line string) (ParsedLine, error) {
@@ -198,14 +121,10 @@ func parseLine(line string) (ParsedLine, error) {
 	// Convert pattern to target bit representation
 	// Each '#' at position i sets bit i to 1
 	// Example: "#.#" → positions 0,2 → binary 101 → decimal 5
-	N := 0
-	for i, c := range line[start+1 : end] {
+	N := helpers.NewBitSet(len(result.Pattern))
+	for i, c := range result.Pattern {
 		if c == '#' {
-			// Set bit i using left shift: 1 << i
-			// Position 0: 1<<0 = 1 (binary: 001)
-			// Position 1: 1<<1 = 2 (binary: 010)
-			// Position 2: 1<<2 = 4 (binary: 100)
-			N += 1 << i
+			N.Set(i)
 		}
 	}
 
@@ -213,8 +132,22 @@ func parseLine(line string) (ParsedLine, error) {
 
 	remaining := line[end+1:]
 
-	// Ignore curly braces section (used in part 2)
+	// Curly braces section holds the joltage targets (used in part 2)
 	if idx := strings.Index(remaining, "{"); idx != -1 {
+		groups, err := parse.Groups(remaining[idx:], '{', '}')
+		if err != nil {
+			return result, err
+		}
+		if len(groups) != 1 {
+			return result, fmt.Errorf("expected one joltage section, got %d", len(groups))
+		}
+		result.Joltage, err = parse.CSV[int64](groups[0], ",")
+		if err != nil {
+			return result, err
+		}
+		if len(result.Joltage) != len(result.Pattern) {
+			return result, fmt.Errorf("got %d joltage targets for %d lights", len(result.Joltage), len(result.Pattern))
+		}
 		remaining = remaining[:idx]
 	}
 
@@ -236,17 +169,19 @@ func parseLine(line string) (ParsedLine, error) {
 
 		// Convert button group to bit representation
 		// Button affecting positions (0,2,3) → bits 0,2,3 set → binary 1101 → decimal 13
-		var s int
+		// Setting rather than adding means a repeated position is harmless
+		s := helpers.NewBitSet(len(result.Pattern))
 		if groupStr != "" {
 			parts := strings.Split(groupStr, ",")
 			for _, part := range parts {
 				num, err := strconv.Atoi(strings.TrimSpace(part))
-				// Set bit at position 'num'
-				// Example: num=3 → 1<<3 = 8 (binary: 1000)
-				s += 1 << num
 				if err != nil {
 					return result, fmt.Errorf("failed to parse number: %v", err)
 				}
+				if num < 0 || num >= len(result.Pattern) {
+					return result, fmt.Errorf("button position %d outside of %d lights", num, len(result.Pattern))
+				}
+				s.Set(num)
 				group = append(group, num)
 			}
 		}
@@ -260,14 +195,403 @@ func parseLine(line string) (ParsedLine, error) {
 }
 
 func Part2(inputFile string) (string, error) {
-	data, err := os.ReadFile(inputFile)
+	answer, err := Part2Answer(inputFile)
+	if err != nil {
+		return "", err
+	}
+	return answer.String(), nil
+}
+
+// Part2Answer also reports how often each button was pressed on each machine
+func Part2Answer(inputFile string) (helpers.Answer, error) {
+	lines, err := helpers.ReadLines(inputFile)
 	if err != nil {
-		return "", fmt.Errorf("failed to read input: %w", err)
+		return helpers.Answer{}, fmt.Errorf("failed to read input: %w", err)
+	}
+
+	var totalPresses helpers.Accumulator
+	var details []helpers.Detail
+	for lineNum, li := range lines {
+		line, err := parseLine(li)
+		if err != nil {
+			return helpers.Answer{}, fmt.Errorf("line %d: %w", lineNum+1, err)
+		}
+		if line.Joltage == nil {
+			return helpers.Answer{}, fmt.Errorf("line %d: missing joltage targets", lineNum+1)
+		}
+
+		presses, err := minJoltagePresses(line)
+		if err != nil {
+			return helpers.Answer{}, fmt.Errorf("line %d: %w", lineNum+1, err)
+		}
+
+		l.Printf("Line %d: joltage %v, presses %v\n", lineNum+1, line.Joltage, presses)
+		details = append(details, helpers.Detail{Key: fmt.Sprintf("machine %d", lineNum+1), Value: fmt.Sprint(presses)})
+		for _, p := range presses {
+			totalPresses.Add(p)
+		}
+	}
+
+	answer := totalPresses.Answer()
+	answer.Details = details
+	return answer, nil
+}
+
+// minJoltagePresses finds how many times to press each button so every
+// counter hits its target, using as few presses as possible in total.
+//
+// Each counter gives an equation sum(x[i] for buttons i touching it) =
+// target, with x[i] >= 0. We row reduce the system exactly, which leaves
+// every pivot button as a function of the few free buttons, and then search
+// the free buttons one at a time. A pivot is worked out as soon as the last
+// free button in its row is fixed, so a branch ends the moment a pivot would
+// be negative or fractional, and each free button only tries the values
+// that leave every row able to give its pivot a value in range. Branches are
+// also cut once lower bounds on the presses still needed show they can't
+// beat the best total so far. A button can never be pressed more often than
+// the smallest target of the counters it touches.
+func minJoltagePresses(line ParsedLine) ([]int64, error) {
+	numButtons := len(line.Buttons)
+	numCounters := len(line.Joltage)
+
+	bound := make([]int64, numButtons)
+	matrix := make(linalg.IntMatrix, numCounters)
+	for c := range numCounters {
+		matrix[c] = make([]int64, numButtons+1)
+		for b, btn := range line.Buttons {
+			if btn.Test(c) {
+				matrix[c][b] = 1
+			}
+		}
+		matrix[c][numButtons] = line.Joltage[c]
+	}
+	for b, btn := range line.Buttons {
+		bound[b] = -1
+		for c := range btn.Bits() {
+			if bound[b] == -1 || line.Joltage[c] < bound[b] {
+				bound[b] = line.Joltage[c]
+			}
+		}
+		// A button that touches nothing is never worth pressing
+		bound[b] = max(bound[b], 0)
+	}
+
+	// Every reduced row is integer valued:
+	// denom*x[pivot] + sum(coeff*x[free]) = rhs
+	reduced, pivots, err := linalg.IntRREF(matrix, numButtons)
+	if err != nil {
+		return nil, fmt.Errorf("machine %s: %w", line.Pattern, err)
+	}
+	for _, row := range reduced[len(pivots):] {
+		if row[numButtons] != 0 {
+			return nil, fmt.Errorf("machine %s can not reach joltage %v", line.Pattern, line.Joltage)
+		}
+	}
+
+	isPivot := make([]bool, numButtons)
+	for _, p := range pivots {
+		isPivot[p] = true
+	}
+	var free []int
+	for b := range numButtons {
+		if !isPivot[b] {
+			free = append(free, b)
+		}
+	}
+
+	// Fix the buttons that cover the most counters first, as they decide
+	// most of the total and quickly tighten the bounds on the rest
+	slices.SortStableFunc(free, func(a, b int) int {
+		return line.Buttons[b].Count() - line.Buttons[a].Count()
+	})
+
+	// Each row reads denom*x[pivot] = rhs - sum(coeff[f]*x[free[f]]), with
+	// denom made positive. rest[f] is the range of the sum over the free
+	// buttons after f, each anywhere within its bound.
+	type row struct {
+		pivot   int
+		denom   int64
+		coeff   []int64
+		rhs     int64
+		restMin []int64
+		restMax []int64
+	}
+	rows := make([]row, len(pivots))
+	// uses[f] are the rows that depend on free button f, and settles[f+1]
+	// the rows whose pivot is known once free buttons 0..f are fixed
+	uses := make([][]int, len(free))
+	settles := make([][]int, len(free)+1)
+	for k, p := range pivots {
+		sign := int64(1)
+		if reduced[k][p] < 0 {
+			sign = -1
+		}
+		r := row{
+			pivot:   p,
+			denom:   sign * reduced[k][p],
+			rhs:     sign * reduced[k][numButtons],
+			coeff:   make([]int64, len(free)),
+			restMin: make([]int64, len(free)),
+			restMax: make([]int64, len(free)),
+		}
+		last := -1
+		for f, b := range free {
+			r.coeff[f] = sign * reduced[k][b]
+			if r.coeff[f] != 0 {
+				uses[f] = append(uses[f], k)
+				last = f
+			}
+		}
+		for f := len(free) - 2; f >= 0; f-- {
+			term := r.coeff[f+1] * bound[free[f+1]]
+			r.restMin[f] = r.restMin[f+1] + min(term, 0)
+			r.restMax[f] = r.restMax[f+1] + max(term, 0)
+		}
+		settles[last+1] = append(settles[last+1], k)
+		rows[k] = r
+	}
+
+	// The total number of presses is linear in the free buttons. Scaled by
+	// the lcm of the denominators it is base + sum(weight[f]*x[free[f]]),
+	// and cheapest[f] is the least the free buttons from f on can add.
+	scale := int64(1)
+	for _, r := range rows {
+		if scale, err = helpers.CheckedMul(scale/gcd(scale, r.denom), r.denom); err != nil {
+			return nil, fmt.Errorf("machine %s: %w", line.Pattern, err)
+		}
+	}
+	var base int64
+	weight := make([]int64, len(free))
+	for f := range free {
+		weight[f] = scale
+	}
+	for _, r := range rows {
+		m := scale / r.denom
+		base += m * r.rhs
+		for f, c := range r.coeff {
+			weight[f] -= m * c
+		}
+	}
+	cheapest := make([]int64, len(free)+1)
+	for f := len(free) - 1; f >= 0; f-- {
+		cheapest[f] = cheapest[f+1] + min(weight[f]*bound[free[f]], 0)
+	}
+
+	// Once free buttons 0..f-1 are fixed, no remaining press covers more
+	// than widest[f] of the joltage still left
+	touches := make([][]int, numButtons)
+	for b, btn := range line.Buttons {
+		touches[b] = slices.Collect(btn.Bits())
+	}
+	widest := make([]int64, len(free)+1)
+	for f := len(free) - 1; f >= 0; f-- {
+		widest[f] = max(widest[f+1], int64(len(touches[free[f]])))
+		for _, k := range settles[f+1] {
+			widest[f] = max(widest[f], int64(len(touches[rows[k].pivot])))
+		}
+	}
+
+	// A press adds at most 2 to sum(dual[c]*left[c]) for any dual with
+	// entries 0, 1 or 2 where the counters each button touches sum to at
+	// most 2, so half that sum bounds the presses still needed. Only the
+	// duals that can't be raised anywhere are kept.
+	var duals [][]int64
+	dual := make([]int64, numCounters)
+	fits := func() bool {
+		for _, ts := range touches {
+			var sum int64
+			for _, c := range ts {
+				sum += dual[c]
+			}
+			if sum > 2 {
+				return false
+			}
+		}
+		return true
+	}
+	var enumerate func(c int)
+	enumerate = func(c int) {
+		if c == numCounters {
+			for i := range dual {
+				if dual[i] < 2 {
+					dual[i]++
+					raised := fits()
+					dual[i]--
+					if raised {
+						return
+					}
+				}
+			}
+			duals = append(duals, slices.Clone(dual))
+			return
+		}
+		for v := int64(2); v >= 0; v-- {
+			dual[c] = v
+			if fits() {
+				enumerate(c + 1)
+			}
+		}
+		dual[c] = 0
+	}
+	enumerate(0)
+
+	// partial[k] is rhs minus the terms of the free buttons fixed so far,
+	// left is the joltage the presses so far haven't covered, and presses is
+	// the one assignment being built up
+	partial := make([]int64, len(rows))
+	for k, r := range rows {
+		partial[k] = r.rhs
+	}
+	left := slices.Clone(line.Joltage)
+	presses := make([]int64, numButtons)
+
+	press := func(b int, n int64) {
+		for _, c := range touches[b] {
+			left[c] -= n
+		}
+	}
+	// settle works out the pivots of the given rows and presses them,
+	// returning how many presses that adds. It fails, pressing nothing, if a
+	// pivot is out of range or not a whole number of presses.
+	settle := func(ks []int) (int64, bool) {
+		for _, k := range ks {
+			r := rows[k]
+			if partial[k]%r.denom != 0 {
+				return 0, false
+			}
+			x := partial[k] / r.denom
+			if x < 0 || x > bound[r.pivot] {
+				return 0, false
+			}
+			presses[r.pivot] = x
+		}
+		var count int64
+		for _, k := range ks {
+			press(rows[k].pivot, presses[rows[k].pivot])
+			count += presses[rows[k].pivot]
+		}
+		return count, true
+	}
+	unsettle := func(ks []int) {
+		for _, k := range ks {
+			press(rows[k].pivot, -presses[rows[k].pivot])
+		}
 	}
 
-	fmt.Println("Day 01, Part 2")
-	fmt.Printf("Input length: %d bytes\n", len(data))
-	fmt.Println("TODO: Implement solution")
+	var best []int64
+	bestTotal := int64(-1)
+	// beaten reports whether a scaled cost can't improve on the best so far
+	beaten := func(cost int64) bool {
+		return bestTotal != -1 && cost > scale*(bestTotal-1)
+	}
+
+	// cost is the scaled total for the free buttons fixed so far, and
+	// pressed the number of presses made so far
+	var search func(f int, cost, pressed int64)
+	search = func(f int, cost, pressed int64) {
+		if beaten(cost + cheapest[f]) {
+			return
+		}
+		if bestTotal != -1 {
+			var most, sum int64
+			for _, n := range left {
+				sum += n
+			}
+			for _, d := range duals {
+				var dot int64
+				for c, n := range left {
+					dot += d[c] * n
+				}
+				most = max(most, ceilDiv(dot, 2))
+			}
+			if widest[f] > 0 {
+				most = max(most, ceilDiv(sum, widest[f]))
+			}
+			if pressed+most >= bestTotal {
+				return
+			}
+		}
+		if f == len(free) {
+			best = append(best[:0], presses...)
+			bestTotal = cost / scale
+			return
+		}
+
+		// Every row using this button needs 0 <= denom*x[pivot] <=
+		// denom*bound, whatever the later free buttons turn out to be
+		lo, hi := int64(0), bound[free[f]]
+		for _, k := range uses[f] {
+			r := rows[k]
+			c := r.coeff[f]
+			upper := partial[k] - r.restMin[f]
+			lower := partial[k] - r.restMax[f] - r.denom*bound[r.pivot]
+			if c > 0 {
+				hi = min(hi, floorDiv(upper, c))
+				lo = max(lo, ceilDiv(lower, c))
+			} else {
+				lo = max(lo, ceilDiv(upper, c))
+				hi = min(hi, floorDiv(lower, c))
+			}
+		}
+		if lo > hi {
+			return
+		}
+
+		// Try the cheaper end first, and stop once the rest can't win
+		v, step, end := lo, int64(1), hi+1
+		if weight[f] < 0 {
+			v, step, end = hi, -1, lo-1
+		}
+		for ; v != end; v += step {
+			next := cost + weight[f]*v
+			if beaten(next + cheapest[f+1]) {
+				break
+			}
+			presses[free[f]] = v
+			press(free[f], v)
+			for _, k := range uses[f] {
+				partial[k] -= rows[k].coeff[f] * v
+			}
+			if count, ok := settle(settles[f+1]); ok {
+				search(f+1, next, pressed+v+count)
+				unsettle(settles[f+1])
+			}
+			for _, k := range uses[f] {
+				partial[k] += rows[k].coeff[f] * v
+			}
+			press(free[f], -v)
+		}
+	}
+	if count, ok := settle(settles[0]); ok {
+		search(0, base, count)
+	}
 
-	return "", nil
+	if best == nil {
+		return nil, fmt.Errorf("machine %s has no non-negative way to reach joltage %v", line.Pattern, line.Joltage)
+	}
+	return best, nil
+}
+
+func gcd(a, b int64) int64 {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
+
+// floorDiv and ceilDiv round a/b down and up, for any signs
+func floorDiv(a, b int64) int64 {
+	q := a / b
+	if a%b != 0 && (a < 0) != (b < 0) {
+		q--
+	}
+	return q
+}
+
+func ceilDiv(a, b int64) int64 {
+	q := a / b
+	if a%b != 0 && (a < 0) == (b < 0) {
+		q++
+	}
+	return q
 }
@@ -1,26 +1,76 @@
 package day10
 
-import "testing"
+import (
+	"slices"
+	"testing"
+)
 
 func TestPart1(t *testing.T) {
 	res, err := Part1("input_test.txt")
 	if err != nil {
 		t.Fatalf("Part1 failed: %v", err)
 	}
-	expected := "1337"
+	expected := "7"
 	if res != expected {
 		t.Errorf("got %s, want %s", res, expected)
 	}
 }
 
-// func TestPart2(t *testing.T) {
-// 	res, err := Part2("input_test.txt")
-// 	if err != nil {
-// 		t.Fatalf("Part2 failed: %v", err)
-// 	}
-//
-// 	expected := "24"
-// 	if res != expected {
-// 		t.Errorf("got %s, want %s", res, expected)
-// 	}
-// }
+func TestPart2(t *testing.T) {
+	res, err := Part2("input_test.txt")
+	if err != nil {
+		t.Fatalf("Part2 failed: %v", err)
+	}
+
+	expected := "33"
+	if res != expected {
+		t.Errorf("got %s, want %s", res, expected)
+	}
+}
+
+func TestMinJoltagePressesInfeasible(t *testing.T) {
+	// Both counters always move together, so they can never differ
+	line, err := parseLine("[##] (0,1) {3,4}")
+	if err != nil {
+		t.Fatalf("parseLine failed: %v", err)
+	}
+	if _, err := minJoltagePresses(line); err == nil {
+		t.Errorf("expected an error for an unreachable target")
+	}
+
+	// Reachable only with a negative press count
+	line, _ = parseLine("[##] (0,1) (0) {1,3}")
+	if _, err := minJoltagePresses(line); err == nil {
+		t.Errorf("expected an error for a target needing negative presses")
+	}
+}
+
+func TestMinJoltagePressesManyFree(t *testing.T) {
+	// Nine buttons on four counters leave five free buttons, each of which
+	// could be pressed up to 170 times
+	line, err := parseLine("[....] (0) (1) (2) (3) (0,1) (1,2) (2,3) (0,3) (0,1,2,3) {200,180,190,170}")
+	if err != nil {
+		t.Fatalf("parseLine failed: %v", err)
+	}
+	presses, err := minJoltagePresses(line)
+	if err != nil {
+		t.Fatalf("minJoltagePresses failed: %v", err)
+	}
+
+	counters := make([]int64, len(line.Joltage))
+	var total int64
+	for b, n := range presses {
+		for c := range line.Buttons[b].Bits() {
+			counters[c] += n
+		}
+		total += n
+	}
+	if !slices.Equal(counters, line.Joltage) {
+		t.Errorf("presses %v reach %v, want %v", presses, counters, line.Joltage)
+	}
+	// The all counters button adds 4 per press, so pressing it 170 times and
+	// finishing with (0,1) 10 times and (0), (2) 20 times each is best
+	if total != 220 {
+		t.Errorf("got %d presses, want 220", total)
+	}
+}
@@ -6,10 +6,9 @@ import (
 	"io"
 	"log"
 	"os"
-	"strconv"
-	"strings"
 
 	"aoc-2025/helpers"
+	"aoc-2025/helpers/graph"
 )
 
 const debugMode = false
@@ -21,122 +20,71 @@ var l = func() *log.Logger {
 	return log.New(io.Discard, "", 0)
 }()
 
-type Node struct {
-	Key      string
-	Children []*Node
-}
-
-type Tree struct {
-	Nodes map[string]*Node
-}
-
-func Newtree(lines []string) *Tree {
-	tree := &Tree{Nodes: make(map[string]*Node)}
-
-	for _, line := range lines {
-		parts := strings.Split(line, ": ")
-		key := parts[0]
-		childKeys := strings.Fields(parts[1])
-
-		node := tree.GetOrCreate(key)
-		for _, child := range childKeys {
-			child := tree.GetOrCreate(child)
-			node.Children = append(node.Children, child)
-		}
-	}
-	return tree
-}
+// Part1Params and Part2Params declare which paths are counted. via and avoid
+// are comma separated lists of nodes every path must pass through, in any
+// order, and nodes no path may touch.
+var (
+	Part1Params = pathParams("you", "")
+	Part2Params = pathParams("svr", "dac,fft")
+)
 
-func (t *Tree) GetOrCreate(key string) *Node {
-	if node, exists := t.Nodes[key]; exists {
-		return node
+func pathParams(start, via string) []helpers.Param {
+	return []helpers.Param{
+		{Name: "start", Kind: helpers.ParamString, Default: start, Usage: "device the paths start at"},
+		{Name: "end", Kind: helpers.ParamString, Default: "out", Usage: "device the paths end at"},
+		{Name: "via", Kind: helpers.ParamString, Default: via, Usage: "devices every path must visit"},
+		{Name: "avoid", Kind: helpers.ParamString, Default: "", Usage: "devices no path may visit"},
 	}
-
-	node := &Node{Key: key, Children: []*Node{}}
-	t.Nodes[key] = node
-	return node
 }
 
 func Part1(inputFile string) (string, error) {
-	lines, err := helpers.ReadLines(inputFile)
-	if err != nil {
-		return "", fmt.Errorf("failed to read input: %w", err)
-	}
-
-	tree := Newtree(lines)
-	seen := make(map[string]int64)
-
-	a := getOut(tree, "you", seen)
-
-	l.Printf("Res %d `n", a)
-
-	return strconv.FormatInt(a, 10), nil
+	return Part1With(inputFile, nil)
 }
 
-func getOut(tree *Tree, next string, seen map[string]int64) int64 {
-	// base case
-	if next == "out" {
-		return 1
-	}
+func Part2(inputFile string) (string, error) {
+	return Part2With(inputFile, nil)
+}
 
-	// Check if we have been here before
-	if val, exists := seen[next]; exists {
-		return val
-	}
+func Part1With(inputFile string, params helpers.Params) (string, error) {
+	return countPaths(inputFile, Part1Params, params)
+}
 
-	var paths int64
+func Part2With(inputFile string, params helpers.Params) (string, error) {
+	return countPaths(inputFile, Part2Params, params)
+}
 
-	if node, exists := tree.Nodes[next]; exists {
-		for _, child := range node.Children {
-			paths += getOut(tree, child.Key, seen)
-		}
+// countPaths counts the paths through the device graph in one topological
+// pass, tracking which waypoints each partial path has visited as a bitmask.
+// A cycle between start and end is reported with the devices on it.
+func countPaths(inputFile string, decls []helpers.Param, params helpers.Params) (string, error) {
+	values, err := helpers.Resolve(decls, params)
+	if err != nil {
+		return "", err
 	}
 
-	seen[next] = paths
-	return paths
-}
-
-func Part2(inputFile string) (string, error) {
 	lines, err := helpers.ReadLines(inputFile)
 	if err != nil {
 		return "", fmt.Errorf("failed to read input: %w", err)
 	}
 
-	tree := Newtree(lines)
-	memo := make(map[string]int64)
-
-	count := getOut2(tree, "svr", false, false, memo)
-
-	return strconv.FormatInt(count, 10), nil
-}
-
-func getOut2(tree *Tree, node string, hasDac, hasFft bool, memo map[string]int64) int64 {
-	if node == "dac" {
-		hasDac = true
-	}
-	if node == "fft" {
-		hasFft = true
+	g, err := graph.Parse(lines)
+	if err != nil {
+		return "", err
 	}
 
-	if node == "out" {
-		if hasDac && hasFft {
-			return 1
+	start, end := values.String("start"), values.String("end")
+	via, avoid := values.List("via"), values.List("avoid")
+	for _, k := range append(append([]string{start, end}, via...), avoid...) {
+		if !g.Has(k) {
+			return "", fmt.Errorf("unknown device %q", k)
 		}
-		return 0
-	}
-
-	key := fmt.Sprintf("%s:%t:%t", node, hasDac, hasFft)
-	if cached, exists := memo[key]; exists {
-		return cached
 	}
 
-	var paths int64
-	if treeNode, exists := tree.Nodes[node]; exists {
-		for _, child := range treeNode.Children {
-			paths += getOut2(tree, child.Key, hasDac, hasFft, memo)
-		}
+	count, err := g.CountPathsBig(start, end, via, avoid)
+	if err != nil {
+		return "", err
 	}
+	l.Printf("%s -> %s via %v avoiding %v: %s paths\n", start, end, via, avoid, count)
 
-	memo[key] = paths
-	return paths
+	return count.String(), nil
 }
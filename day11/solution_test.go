@@ -1,6 +1,14 @@
 package day11
 
-import "testing"
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"aoc-2025/helpers"
+	"aoc-2025/helpers/graph"
+	"aoc-2025/helpers/inputtest"
+)
 
 func TestPart1(t *testing.T) {
 	res, err := Part1("input_test.txt")
@@ -25,3 +33,53 @@ func TestPart2(t *testing.T) {
 		t.Errorf("got %s, want %s", res, expected)
 	}
 }
+
+func TestPathParams(t *testing.T) {
+	tests := []struct {
+		part      func(string, helpers.Params) (string, error)
+		inputFile string
+		params    helpers.Params
+		expected  string
+	}{
+		{Part2With, "input_test_2.txt", helpers.Params{"via": ""}, "8"},
+		{Part2With, "input_test_2.txt", helpers.Params{"via": "fft", "avoid": "dac"}, "2"},
+		{Part2With, "input_test_2.txt", helpers.Params{"start": "bbb", "via": "hub"}, "2"},
+		{Part1With, "input_test.txt", helpers.Params{"start": "hhh"}, "5"},
+		{Part1With, "input_test.txt", helpers.Params{"end": "ddd"}, "2"},
+	}
+	for _, tt := range tests {
+		res, err := tt.part(tt.inputFile, tt.params)
+		if err != nil {
+			t.Fatalf("%s %v: %v", tt.inputFile, tt.params, err)
+		}
+		if res != tt.expected {
+			t.Errorf("%s %v: got %s, want %s", tt.inputFile, tt.params, res, tt.expected)
+		}
+	}
+
+	if _, err := Part2With("input_test_2.txt", helpers.Params{"via": "dac,nope"}); err == nil {
+		t.Error("expected an error for an unknown waypoint")
+	}
+	if _, err := Part1With("input_test.txt", helpers.Params{"avoid": "nope"}); err == nil {
+		t.Error("expected an error for an unknown device to avoid")
+	}
+}
+
+func TestCycle(t *testing.T) {
+	input := inputtest.Write(t, "you: aaa", "aaa: bbb out", "bbb: ccc", "ccc: aaa")
+
+	_, err := Part1(input)
+	var cycleErr *graph.CycleError[string]
+	if !errors.As(err, &cycleErr) {
+		t.Fatalf("expected a cycle error, got %v", err)
+	}
+	if got := strings.Join(cycleErr.Cycle, " "); got != "aaa bbb ccc aaa" {
+		t.Errorf("got cycle %s, want aaa bbb ccc aaa", got)
+	}
+
+	// Avoiding the cycle leaves a single path
+	res, err := Part1With(input, helpers.Params{"avoid": "ccc"})
+	if err != nil || res != "1" {
+		t.Errorf("got %s, %v, want 1", res, err)
+	}
+}
@@ -0,0 +1,168 @@
+package helpers
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strconv"
+	"time"
+)
+
+type AnswerKind int
+
+const (
+	AnswerText AnswerKind = iota
+	AnswerInt
+	AnswerBig
+)
+
+func (k AnswerKind) String() string {
+	switch k {
+	case AnswerInt:
+		return "int"
+	case AnswerBig:
+		return "big"
+	default:
+		return "text"
+	}
+}
+
+// Detail is a named piece of extra information about how an answer was found
+type Detail struct {
+	Key   string
+	Value string
+}
+
+// Timing is how long one stage of a solution took
+type Timing struct {
+	Name     string
+	Duration time.Duration
+}
+
+// Answer is what a solution found: an int64, a big.Int or text, with
+// optional details and timings for the runner to show alongside it. The
+// builder methods return a copy, so an Answer can be extended step by step.
+type Answer struct {
+	kind    AnswerKind
+	n       int64
+	big     *big.Int
+	text    string
+	Details []Detail
+	Timings []Timing
+}
+
+func IntAnswer(n int64) Answer {
+	return Answer{kind: AnswerInt, n: n}
+}
+
+// BigAnswer keeps b as an int64 answer if it fits
+func BigAnswer(b *big.Int) Answer {
+	if b.IsInt64() {
+		return IntAnswer(b.Int64())
+	}
+	return Answer{kind: AnswerBig, big: new(big.Int).Set(b)}
+}
+
+func TextAnswer(s string) Answer {
+	return Answer{kind: AnswerText, text: s}
+}
+
+// ParseAnswer adapts the string a solution used to return: an integer
+// written the way the number would print becomes a number, and anything
+// else, such as "+5" or "007", stays text so the answer is shown unchanged
+func ParseAnswer(s string) Answer {
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil && strconv.FormatInt(n, 10) == s {
+		return IntAnswer(n)
+	}
+	if b, ok := new(big.Int).SetString(s, 10); ok && b.String() == s {
+		return BigAnswer(b)
+	}
+	return TextAnswer(s)
+}
+
+func (a Accumulator) Answer() Answer {
+	if n, fits := a.Int64(); fits {
+		return IntAnswer(n)
+	}
+	return BigAnswer(a.Big())
+}
+
+// With adds a detail, formatting value with fmt.Sprint
+func (a Answer) With(key string, value any) Answer {
+	a.Details = append(a.Details[:len(a.Details):len(a.Details)], Detail{Key: key, Value: fmt.Sprint(value)})
+	return a
+}
+
+// Timed adds how long the named stage took
+func (a Answer) Timed(name string, d time.Duration) Answer {
+	a.Timings = append(a.Timings[:len(a.Timings):len(a.Timings)], Timing{Name: name, Duration: d})
+	return a
+}
+
+func (a Answer) Kind() AnswerKind {
+	return a.kind
+}
+
+// Int64 returns the answer and whether it is an int64
+func (a Answer) Int64() (int64, bool) {
+	return a.n, a.kind == AnswerInt
+}
+
+// Big returns the answer as a new big.Int, if it is a number
+func (a Answer) Big() (*big.Int, bool) {
+	switch a.kind {
+	case AnswerInt:
+		return big.NewInt(a.n), true
+	case AnswerBig:
+		return new(big.Int).Set(a.big), true
+	default:
+		return nil, false
+	}
+}
+
+func (a Answer) String() string {
+	switch a.kind {
+	case AnswerInt:
+		return strconv.FormatInt(a.n, 10)
+	case AnswerBig:
+		return a.big.String()
+	default:
+		return a.text
+	}
+}
+
+// MarshalJSON writes numbers as JSON numbers, whatever their size, and
+// timings in milliseconds
+func (a Answer) MarshalJSON() ([]byte, error) {
+	type detail struct {
+		Key   string `json:"key"`
+		Value string `json:"value"`
+	}
+	type timing struct {
+		Name string  `json:"name"`
+		Ms   float64 `json:"ms"`
+	}
+	out := struct {
+		Kind    string          `json:"kind"`
+		Value   json.RawMessage `json:"value"`
+		Details []detail        `json:"details,omitempty"`
+		Timings []timing        `json:"timings,omitempty"`
+	}{Kind: a.kind.String()}
+
+	if a.kind == AnswerText {
+		text, err := json.Marshal(a.text)
+		if err != nil {
+			return nil, err
+		}
+		out.Value = text
+	} else {
+		out.Value = json.RawMessage(a.String())
+	}
+	for _, d := range a.Details {
+		out.Details = append(out.Details, detail{Key: d.Key, Value: d.Value})
+	}
+	for _, t := range a.Timings {
+		out.Timings = append(out.Timings, timing{Name: t.Name, Ms: float64(t.Duration.Microseconds()) / 1000.0})
+	}
+	return json.Marshal(out)
+}
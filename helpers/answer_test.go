@@ -0,0 +1,61 @@
+package helpers
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestParseAnswer(t *testing.T) {
+	tests := []struct {
+		in   string
+		kind AnswerKind
+	}{
+		{"42", AnswerInt},
+		{"-7", AnswerInt},
+		{"18446744073709551616", AnswerBig},
+		{"+5", AnswerText},
+		{"007", AnswerText},
+		{"-0", AnswerText},
+		{"+18446744073709551616", AnswerText},
+		{"018446744073709551616", AnswerText},
+		{"EFHKLO", AnswerText},
+		{"", AnswerText},
+	}
+	for _, tt := range tests {
+		a := ParseAnswer(tt.in)
+		if a.Kind() != tt.kind || a.String() != tt.in {
+			t.Errorf("ParseAnswer(%q): got %v %q", tt.in, a.Kind(), a.String())
+		}
+	}
+}
+
+func TestAnswerDetails(t *testing.T) {
+	base := IntAnswer(24).With("from", "9,5")
+	a := base.With("to", "2,3").Timed("search", 1500*time.Microsecond)
+	b := base.With("to", "11,7")
+
+	// Extending one answer must not change another built from the same base
+	if a.Details[1].Value != "2,3" || b.Details[1].Value != "11,7" {
+		t.Errorf("got %v and %v", a.Details, b.Details)
+	}
+
+	got, err := json.Marshal(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"kind":"int","value":24,"details":[{"key":"from","value":"9,5"},{"key":"to","value":"2,3"}],"timings":[{"name":"search","ms":1.5}]}`
+	if string(got) != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+
+	var acc Accumulator
+	acc.Add(1 << 62)
+	acc.Mul(4)
+	if got, _ := json.Marshal(acc.Answer()); string(got) != `{"kind":"big","value":18446744073709551616}` {
+		t.Errorf("got %s", got)
+	}
+	if got, _ := json.Marshal(TextAnswer("a\"b")); string(got) != `{"kind":"text","value":"a\"b"}` {
+		t.Errorf("got %s", got)
+	}
+}
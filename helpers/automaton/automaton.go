@@ -0,0 +1,287 @@
+// Package automaton runs cellular automata on a rectangular grid.
+package automaton
+
+import (
+	"hash/maphash"
+	"slices"
+
+	"aoc-2025/helpers"
+)
+
+// Grid is a W x H grid of cells stored row by row
+type Grid[T comparable] struct {
+	W     int
+	H     int
+	Cells []T
+}
+
+func NewGrid[T comparable](w, h int) *Grid[T] {
+	return &Grid[T]{W: w, H: h, Cells: make([]T, w*h)}
+}
+
+// GridFromLines builds a byte grid, padding short lines with pad
+func GridFromLines(lines []string, pad byte) *Grid[byte] {
+	w := 0
+	for _, line := range lines {
+		w = max(w, len(line))
+	}
+	g := NewGrid[byte](w, len(lines))
+	for y, line := range lines {
+		for x := range w {
+			if x < len(line) {
+				g.Cells[y*w+x] = line[x]
+			} else {
+				g.Cells[y*w+x] = pad
+			}
+		}
+	}
+	return g
+}
+
+func (g *Grid[T]) In(x, y int) bool {
+	return x >= 0 && x < g.W && y >= 0 && y < g.H
+}
+
+func (g *Grid[T]) At(x, y int) T {
+	return g.Cells[y*g.W+x]
+}
+
+func (g *Grid[T]) Set(x, y int, v T) {
+	g.Cells[y*g.W+x] = v
+}
+
+// XY turns a cell index back into coordinates
+func (g *Grid[T]) XY(i int) (int, int) {
+	return i % g.W, i / g.W
+}
+
+// Count returns how many cells equal v
+func (g *Grid[T]) Count(v T) int {
+	acc := 0
+	for _, c := range g.Cells {
+		if c == v {
+			acc++
+		}
+	}
+	return acc
+}
+
+type Offset struct {
+	X int
+	Y int
+}
+
+var (
+	// VonNeumann is the 4 orthogonal neighbours
+	VonNeumann = []Offset{{0, -1}, {-1, 0}, {1, 0}, {0, 1}}
+	// Moore is all 8 surrounding cells
+	Moore = []Offset{
+		{-1, -1}, {0, -1}, {1, -1}, // top
+		{-1, 0}, {1, 0}, // same row
+		{-1, 1}, {0, 1}, {1, 1}, // bottom
+	}
+)
+
+// Rule returns the next value of a cell given its current value and the
+// values of its neighbours that are inside the grid.
+type Rule[T comparable] func(cell T, neighbours []T) T
+
+type Mode int
+
+const (
+	// Synchronous computes every cell of a generation from the previous one
+	Synchronous Mode = iota
+	// Asynchronous updates cells in place in row order, so a cell sees the
+	// new value of neighbours that came before it in the same generation
+	Asynchronous
+)
+
+type StopReason int
+
+const (
+	FixedPoint StopReason = iota // a generation changed nothing
+	StepLimit                    // MaxSteps generations ran
+	Cycle                        // the grid went back to an earlier state
+)
+
+func (r StopReason) String() string {
+	switch r {
+	case FixedPoint:
+		return "fixed point"
+	case StepLimit:
+		return "step limit"
+	case Cycle:
+		return "cycle"
+	}
+	return "unknown"
+}
+
+type Config[T comparable] struct {
+	// Neighbourhood defaults to Moore
+	Neighbourhood []Offset
+	Rule          Rule[T]
+	Mode          Mode
+	// MaxSteps stops after this many generations, 0 means no limit
+	MaxSteps int
+	// DetectCycle stops when the grid repeats an earlier state. It keeps a
+	// copy of every generation, so only turn it on when cycles are expected.
+	DetectCycle bool
+	// OnGeneration is called after every generation with the indexes of the
+	// cells that changed
+	OnGeneration func(gen int, g *Grid[T], changed []int)
+}
+
+type Result struct {
+	Generations int
+	Reason      StopReason
+	// CycleStart and CycleLength are set when Reason is Cycle
+	CycleStart  int
+	CycleLength int
+}
+
+// Run updates g in place until one of the stopping conditions is met.
+//
+// Only cells with a changed neighbour can change, so after the first
+// generation only those are evaluated. They are kept as a worklist of
+// indexes, taken in increasing order, so a generation costs time in
+// proportion to the cells around the last changes rather than the grid.
+func Run[T comparable](g *Grid[T], cfg Config[T]) Result {
+	hood := cfg.Neighbourhood
+	if hood == nil {
+		hood = Moore
+	}
+	n := len(g.Cells)
+
+	// active holds the cells to evaluate this generation and queued the
+	// ones for the next, with the flags keeping each cell in once
+	less := func(a, b int) bool { return a < b }
+	active, queued := helpers.NewHeap(less), helpers.NewHeap(less)
+	inActive, inQueued := make([]bool, n), make([]bool, n)
+	for i := range n {
+		active.Push(i)
+		inActive[i] = true
+	}
+	queue := func(j int) {
+		if !inQueued[j] {
+			inQueued[j] = true
+			queued.Push(j)
+		}
+	}
+
+	buf := make([]T, 0, len(hood))
+	next := slices.Clone(g.Cells)
+
+	neighbours := func(cells []T, i int) []T {
+		x, y := g.XY(i)
+		buf = buf[:0]
+		for _, o := range hood {
+			if g.In(x+o.X, y+o.Y) {
+				buf = append(buf, cells[(y+o.Y)*g.W+x+o.X])
+			}
+		}
+		return buf
+	}
+	// markDependents flags every cell that has i in its neighbourhood
+	markDependents := func(i int, mark func(j int)) {
+		x, y := g.XY(i)
+		mark(i)
+		for _, o := range hood {
+			if g.In(x-o.X, y-o.Y) {
+				mark((y-o.Y)*g.W + x - o.X)
+			}
+		}
+	}
+
+	var history *cycleTracker[T]
+	if cfg.DetectCycle {
+		history = newCycleTracker[T]()
+		history.seen(g.Cells, 0)
+	}
+
+	for gen := 1; ; gen++ {
+		var changed []int
+		switch cfg.Mode {
+		case Synchronous:
+			for i, ok := active.Pop(); ok; i, ok = active.Pop() {
+				inActive[i] = false
+				if v := cfg.Rule(g.Cells[i], neighbours(g.Cells, i)); v != g.Cells[i] {
+					next[i] = v
+					changed = append(changed, i)
+				}
+			}
+			for _, i := range changed {
+				g.Cells[i] = next[i]
+				markDependents(i, queue)
+			}
+		case Asynchronous:
+			for i, ok := active.Pop(); ok; i, ok = active.Pop() {
+				inActive[i] = false
+				if v := cfg.Rule(g.Cells[i], neighbours(g.Cells, i)); v != g.Cells[i] {
+					g.Cells[i] = v
+					changed = append(changed, i)
+					markDependents(i, func(j int) {
+						// Later cells still get looked at in this generation
+						if j > i {
+							if !inActive[j] {
+								inActive[j] = true
+								active.Push(j)
+							}
+						} else {
+							queue(j)
+						}
+					})
+				}
+			}
+		}
+
+		if len(changed) == 0 {
+			return Result{Generations: gen - 1, Reason: FixedPoint}
+		}
+		if cfg.OnGeneration != nil {
+			cfg.OnGeneration(gen, g, changed)
+		}
+		if history != nil {
+			if start, found := history.seen(g.Cells, gen); found {
+				return Result{Generations: gen, Reason: Cycle, CycleStart: start, CycleLength: gen - start}
+			}
+		}
+		if cfg.MaxSteps > 0 && gen >= cfg.MaxSteps {
+			return Result{Generations: gen, Reason: StepLimit}
+		}
+
+		// active was drained, so its flags are all false again
+		active, queued = queued, active
+		inActive, inQueued = inQueued, inActive
+	}
+}
+
+// cycleTracker remembers every state by hash and keeps the states so a hash
+// collision can not be mistaken for a cycle.
+type cycleTracker[T comparable] struct {
+	seed   maphash.Seed
+	states map[uint64][]int
+	grids  [][]T
+}
+
+func newCycleTracker[T comparable]() *cycleTracker[T] {
+	return &cycleTracker[T]{seed: maphash.MakeSeed(), states: make(map[uint64][]int)}
+}
+
+// seen records cells as generation gen and returns the generation it was
+// first seen in if it is a repeat
+func (c *cycleTracker[T]) seen(cells []T, gen int) (int, bool) {
+	var h maphash.Hash
+	h.SetSeed(c.seed)
+	for _, v := range cells {
+		maphash.WriteComparable(&h, v)
+	}
+	sum := h.Sum64()
+	for _, earlier := range c.states[sum] {
+		if slices.Equal(c.grids[earlier], cells) {
+			return earlier, true
+		}
+	}
+	c.states[sum] = append(c.states[sum], gen)
+	c.grids = append(c.grids, slices.Clone(cells))
+	return 0, false
+}
@@ -0,0 +1,114 @@
+package automaton
+
+import (
+	"strings"
+	"testing"
+)
+
+func life(cell byte, neighbours []byte) byte {
+	alive := 0
+	for _, n := range neighbours {
+		if n == '#' {
+			alive++
+		}
+	}
+	if alive == 3 || (cell == '#' && alive == 2) {
+		return '#'
+	}
+	return '.'
+}
+
+func render(g *Grid[byte]) string {
+	var sb strings.Builder
+	for y := range g.H {
+		sb.Write(g.Cells[y*g.W : (y+1)*g.W])
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
+
+func TestBlinkerCycle(t *testing.T) {
+	g := GridFromLines([]string{
+		".....",
+		"..#..",
+		"..#..",
+		"..#..",
+		".....",
+	}, '.')
+
+	gens := 0
+	res := Run(g, Config[byte]{
+		Rule:         life,
+		DetectCycle:  true,
+		OnGeneration: func(int, *Grid[byte], []int) { gens++ },
+	})
+	if res.Reason != Cycle || res.CycleStart != 0 || res.CycleLength != 2 {
+		t.Errorf("got %+v, want a cycle of length 2 from generation 0", res)
+	}
+	if gens != 2 {
+		t.Errorf("got %d callbacks, want 2", gens)
+	}
+}
+
+func TestStepLimit(t *testing.T) {
+	g := GridFromLines([]string{".#.", ".#.", ".#."}, '.')
+	res := Run(g, Config[byte]{Rule: life, MaxSteps: 1})
+	if res.Reason != StepLimit || res.Generations != 1 {
+		t.Errorf("got %+v", res)
+	}
+	if got := render(g); got != "...\n###\n...\n" {
+		t.Errorf("got\n%s", got)
+	}
+}
+
+// spread turns a cell on if any neighbour is on
+func spread(cell byte, neighbours []byte) byte {
+	for _, n := range neighbours {
+		if n == '#' {
+			return '#'
+		}
+	}
+	return cell
+}
+
+func TestModes(t *testing.T) {
+	lines := []string{"#....", "....."}
+
+	sync := GridFromLines(lines, '.')
+	res := Run(sync, Config[byte]{Neighbourhood: VonNeumann, Rule: spread, MaxSteps: 1})
+	if got := sync.Count('#'); got != 3 || res.Reason != StepLimit {
+		t.Errorf("synchronous: got %d cells after one step, want 3", got)
+	}
+
+	// In place updates let the change run along the row in one generation
+	async := GridFromLines(lines, '.')
+	Run(async, Config[byte]{Neighbourhood: VonNeumann, Rule: spread, Mode: Asynchronous, MaxSteps: 1})
+	if got := async.Count('#'); got != 10 {
+		t.Errorf("asynchronous: got %d cells after one step, want 10", got)
+	}
+
+	res = Run(sync, Config[byte]{Neighbourhood: VonNeumann, Rule: spread})
+	if res.Reason != FixedPoint || sync.Count('#') != 10 {
+		t.Errorf("got %+v with %d cells, want fixed point with 10", res, sync.Count('#'))
+	}
+}
+
+func TestOnlyActiveCellsEvaluated(t *testing.T) {
+	g := NewGrid[byte](100, 100)
+	g.Set(50, 50, '#')
+
+	calls := 0
+	res := Run(g, Config[byte]{
+		Rule: func(cell byte, _ []byte) byte {
+			calls++
+			return 0
+		},
+	})
+	if res.Reason != FixedPoint || res.Generations != 1 {
+		t.Errorf("got %+v, want a fixed point after 1 generation", res)
+	}
+	// Every cell once, then only the changed cell and its 8 neighbours
+	if want := 100*100 + 9; calls != want {
+		t.Errorf("got %d rule calls, want %d", calls, want)
+	}
+}
@@ -0,0 +1,200 @@
+package helpers
+
+import (
+	"encoding/binary"
+	"iter"
+	"math/bits"
+	"strings"
+)
+
+// BitSet is a set of non negative ints backed by 64 bit words. It grows as
+// needed, so the width is only a hint used when printing. Two sets are Equal
+// when they have the same bits set, whatever their width.
+type BitSet struct {
+	words []uint64
+	width int
+}
+
+// NewBitSet returns an empty set sized for bits 0..width-1
+func NewBitSet(width int) *BitSet {
+	return &BitSet{words: make([]uint64, (width+63)/64), width: width}
+}
+
+// BitSetOf returns a set with the given bits set
+func BitSetOf(width int, bits ...int) *BitSet {
+	b := NewBitSet(width)
+	for _, i := range bits {
+		b.Set(i)
+	}
+	return b
+}
+
+func (b *BitSet) grow(i int) {
+	for len(b.words) <= i/64 {
+		b.words = append(b.words, 0)
+	}
+	if i >= b.width {
+		b.width = i + 1
+	}
+}
+
+// fit makes b at least as wide as o
+func (b *BitSet) fit(o *BitSet) {
+	for len(b.words) < len(o.words) {
+		b.words = append(b.words, 0)
+	}
+	b.width = max(b.width, o.width)
+}
+
+// Len returns the width of the set
+func (b *BitSet) Len() int {
+	return b.width
+}
+
+func (b *BitSet) Set(i int) *BitSet {
+	b.grow(i)
+	b.words[i/64] |= 1 << (i % 64)
+	return b
+}
+
+func (b *BitSet) Clear(i int) *BitSet {
+	if i/64 < len(b.words) {
+		b.words[i/64] &^= 1 << (i % 64)
+	}
+	return b
+}
+
+func (b *BitSet) Toggle(i int) *BitSet {
+	b.grow(i)
+	b.words[i/64] ^= 1 << (i % 64)
+	return b
+}
+
+func (b *BitSet) Test(i int) bool {
+	return i/64 < len(b.words) && b.words[i/64]&(1<<(i%64)) != 0
+}
+
+func (b *BitSet) Clone() *BitSet {
+	return &BitSet{words: append([]uint64(nil), b.words...), width: b.width}
+}
+
+// Xor, And and Or update b in place and return it so calls can be chained.
+// Use Clone first to keep the original.
+func (b *BitSet) Xor(o *BitSet) *BitSet {
+	b.fit(o)
+	for i, w := range o.words {
+		b.words[i] ^= w
+	}
+	return b
+}
+
+func (b *BitSet) And(o *BitSet) *BitSet {
+	for i := range b.words {
+		if i < len(o.words) {
+			b.words[i] &= o.words[i]
+		} else {
+			b.words[i] = 0
+		}
+	}
+	return b
+}
+
+func (b *BitSet) Or(o *BitSet) *BitSet {
+	b.fit(o)
+	for i, w := range o.words {
+		b.words[i] |= w
+	}
+	return b
+}
+
+// Count returns the number of set bits
+func (b *BitSet) Count() int {
+	acc := 0
+	for _, w := range b.words {
+		acc += bits.OnesCount64(w)
+	}
+	return acc
+}
+
+func (b *BitSet) IsZero() bool {
+	for _, w := range b.words {
+		if w != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Bits iterates over the set bits in ascending order
+func (b *BitSet) Bits() iter.Seq[int] {
+	return func(yield func(int) bool) {
+		for i, w := range b.words {
+			for w != 0 {
+				bit := bits.TrailingZeros64(w)
+				if !yield(i*64 + bit) {
+					return
+				}
+				w &= w - 1
+			}
+		}
+	}
+}
+
+// trimmed drops trailing zero words so equal sets look the same
+func (b *BitSet) trimmed() []uint64 {
+	n := len(b.words)
+	for n > 0 && b.words[n-1] == 0 {
+		n--
+	}
+	return b.words[:n]
+}
+
+func (b *BitSet) Equal(o *BitSet) bool {
+	x, y := b.trimmed(), o.trimmed()
+	if len(x) != len(y) {
+		return false
+	}
+	for i := range x {
+		if x[i] != y[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Key returns a string that is equal for equal sets, for use as a map key
+func (b *BitSet) Key() string {
+	words := b.trimmed()
+	buf := make([]byte, 0, len(words)*8)
+	for _, w := range words {
+		buf = binary.LittleEndian.AppendUint64(buf, w)
+	}
+	return string(buf)
+}
+
+// Hash returns a FNV-1a hash of the set bits
+func (b *BitSet) Hash() uint64 {
+	h := uint64(14695981039346656037)
+	for _, w := range b.trimmed() {
+		for range 8 {
+			h ^= w & 0xff
+			h *= 1099511628211
+			w >>= 8
+		}
+	}
+	return h
+}
+
+// String prints bit 0 first, using # for set and . for clear like the puzzles
+func (b *BitSet) String() string {
+	var sb strings.Builder
+	sb.Grow(b.width)
+	for i := range b.width {
+		if b.Test(i) {
+			sb.WriteByte('#')
+		} else {
+			sb.WriteByte('.')
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,63 @@
+package helpers
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestBitSet(t *testing.T) {
+	b := NewBitSet(4)
+	b.Set(0).Set(2).Set(2).Toggle(3).Toggle(3).Set(130)
+
+	if got := b.Count(); got != 3 {
+		t.Errorf("got %d bits, want 3", got)
+	}
+	if got := slices.Collect(b.Bits()); !slices.Equal(got, []int{0, 2, 130}) {
+		t.Errorf("got %v, want [0 2 130]", got)
+	}
+	if b.Len() != 131 {
+		t.Errorf("got width %d, want 131", b.Len())
+	}
+
+	b.Clear(130)
+	if got := b.String(); got[:4] != "#.#." {
+		t.Errorf("got %s, want #.#. prefix", got[:4])
+	}
+	if !b.Equal(BitSetOf(3, 0, 2)) {
+		t.Errorf("expected equal regardless of width")
+	}
+}
+
+func TestBitSetOps(t *testing.T) {
+	a := BitSetOf(70, 0, 1, 65)
+	b := BitSetOf(70, 1, 2, 65)
+
+	if got := slices.Collect(a.Clone().Xor(b).Bits()); !slices.Equal(got, []int{0, 2}) {
+		t.Errorf("Xor: got %v", got)
+	}
+	if got := slices.Collect(a.Clone().And(b).Bits()); !slices.Equal(got, []int{1, 65}) {
+		t.Errorf("And: got %v", got)
+	}
+	if got := slices.Collect(a.Clone().Or(b).Bits()); !slices.Equal(got, []int{0, 1, 2, 65}) {
+		t.Errorf("Or: got %v", got)
+	}
+	if !a.Clone().Xor(a).IsZero() {
+		t.Errorf("expected a ^ a to be empty")
+	}
+}
+
+func TestBitSetKey(t *testing.T) {
+	seen := map[string]int{}
+	seen[BitSetOf(10, 3).Key()] = 1
+	wide := NewBitSet(200).Set(3)
+
+	if seen[wide.Key()] != 1 {
+		t.Errorf("expected equal sets to share a key")
+	}
+	if BitSetOf(10, 3).Hash() != wide.Hash() {
+		t.Errorf("expected equal sets to share a hash")
+	}
+	if BitSetOf(10, 4).Key() == wide.Key() {
+		t.Errorf("expected different sets to have different keys")
+	}
+}
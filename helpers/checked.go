@@ -0,0 +1,129 @@
+package helpers
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"strconv"
+)
+
+var ErrOverflow = errors.New("int64 overflow")
+
+// CheckedAdd returns a+b, or ErrOverflow if it doesn't fit in an int64
+func CheckedAdd(a, b int64) (int64, error) {
+	c := a + b
+	if (b > 0 && c < a) || (b < 0 && c > a) {
+		return 0, ErrOverflow
+	}
+	return c, nil
+}
+
+// CheckedSub returns a-b, or ErrOverflow if it doesn't fit in an int64
+func CheckedSub(a, b int64) (int64, error) {
+	c := a - b
+	if (b > 0 && c > a) || (b < 0 && c < a) {
+		return 0, ErrOverflow
+	}
+	return c, nil
+}
+
+// CheckedMul returns a*b, or ErrOverflow if it doesn't fit in an int64
+func CheckedMul(a, b int64) (int64, error) {
+	if a == 0 || b == 0 {
+		return 0, nil
+	}
+	c := a * b
+	if c/b != a || (a == -1 && b == math.MinInt64) || (b == -1 && a == math.MinInt64) {
+		return 0, ErrOverflow
+	}
+	return c, nil
+}
+
+// Accumulator is an integer that is kept as an int64 while it fits and
+// switches to a big.Int the first time a result would overflow, so sums and
+// products never silently wrap. The zero value is 0, and copies don't share
+// state.
+type Accumulator struct {
+	n   int64
+	big *big.Int
+}
+
+func NewAccumulator(n int64) Accumulator {
+	return Accumulator{n: n}
+}
+
+func (a *Accumulator) Add(n int64) {
+	if a.big == nil {
+		if c, err := CheckedAdd(a.n, n); err == nil {
+			a.n = c
+			return
+		}
+	}
+	a.AddBig(big.NewInt(n))
+}
+
+func (a *Accumulator) Sub(n int64) {
+	if a.big == nil {
+		if c, err := CheckedSub(a.n, n); err == nil {
+			a.n = c
+			return
+		}
+	}
+	a.set(new(big.Int).Sub(a.Big(), big.NewInt(n)))
+}
+
+func (a *Accumulator) Mul(n int64) {
+	if a.big == nil {
+		if c, err := CheckedMul(a.n, n); err == nil {
+			a.n = c
+			return
+		}
+	}
+	a.set(new(big.Int).Mul(a.Big(), big.NewInt(n)))
+}
+
+func (a *Accumulator) AddBig(b *big.Int) {
+	a.set(new(big.Int).Add(a.Big(), b))
+}
+
+// AddAccumulator adds o, staying on int64 when both fit
+func (a *Accumulator) AddAccumulator(o Accumulator) {
+	if o.big == nil {
+		a.Add(o.n)
+		return
+	}
+	a.AddBig(o.big)
+}
+
+// set stores b, going back to int64 if it fits again
+func (a *Accumulator) set(b *big.Int) {
+	if b.IsInt64() {
+		a.n, a.big = b.Int64(), nil
+		return
+	}
+	a.n, a.big = 0, b
+}
+
+func (a Accumulator) IsZero() bool {
+	return a.big == nil && a.n == 0
+}
+
+// Int64 returns the value and whether it fits in an int64
+func (a Accumulator) Int64() (int64, bool) {
+	return a.n, a.big == nil
+}
+
+// Big returns the value as a new big.Int
+func (a Accumulator) Big() *big.Int {
+	if a.big != nil {
+		return new(big.Int).Set(a.big)
+	}
+	return big.NewInt(a.n)
+}
+
+func (a Accumulator) String() string {
+	if a.big != nil {
+		return a.big.String()
+	}
+	return strconv.FormatInt(a.n, 10)
+}
@@ -0,0 +1,61 @@
+package helpers
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestChecked(t *testing.T) {
+	if _, err := CheckedAdd(math.MaxInt64, 1); !errors.Is(err, ErrOverflow) {
+		t.Errorf("MaxInt64+1: got %v, want ErrOverflow", err)
+	}
+	if _, err := CheckedSub(math.MinInt64, 1); !errors.Is(err, ErrOverflow) {
+		t.Errorf("MinInt64-1: got %v, want ErrOverflow", err)
+	}
+	if _, err := CheckedMul(math.MinInt64, -1); !errors.Is(err, ErrOverflow) {
+		t.Errorf("MinInt64*-1: got %v, want ErrOverflow", err)
+	}
+	if _, err := CheckedMul(1<<32, 1<<31); !errors.Is(err, ErrOverflow) {
+		t.Errorf("2^32*2^31: got %v, want ErrOverflow", err)
+	}
+	if c, err := CheckedMul(-(1 << 31), 1<<32); err != nil || c != math.MinInt64 {
+		t.Errorf("-2^31*2^32: got %d, %v", c, err)
+	}
+}
+
+func TestAccumulator(t *testing.T) {
+	acc := NewAccumulator(math.MaxInt64)
+	acc.Add(1)
+	if _, fits := acc.Int64(); fits || acc.String() != "9223372036854775808" {
+		t.Errorf("got %s, want 2^63 as a big.Int", acc)
+	}
+
+	// Copies are independent once big
+	copied := acc
+	copied.Add(1)
+	if acc.String() != "9223372036854775808" {
+		t.Errorf("adding to a copy changed the original to %s", acc)
+	}
+
+	// Falls back to int64 when the value fits again
+	acc.Sub(1)
+	if n, fits := acc.Int64(); !fits || n != math.MaxInt64 {
+		t.Errorf("got %d, %v, want MaxInt64", n, fits)
+	}
+
+	product := NewAccumulator(1)
+	for range 70 {
+		product.Mul(2)
+	}
+	if product.String() != "1180591620717411303424" {
+		t.Errorf("got %s, want 2^70", product)
+	}
+
+	var sum Accumulator
+	sum.AddAccumulator(product)
+	sum.AddAccumulator(NewAccumulator(-1))
+	if sum.String() != "1180591620717411303423" {
+		t.Errorf("got %s, want 2^70-1", sum)
+	}
+}
@@ -0,0 +1,229 @@
+// Package geom provides exact integer geometry for 2D and 3D points.
+package geom
+
+type Vec2 struct {
+	X int64
+	Y int64
+}
+
+type Vec3 struct {
+	X int64
+	Y int64
+	Z int64
+}
+
+func Abs(x int64) int64 {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+func (v Vec2) Add(o Vec2) Vec2 {
+	return Vec2{X: v.X + o.X, Y: v.Y + o.Y}
+}
+
+func (v Vec2) Sub(o Vec2) Vec2 {
+	return Vec2{X: v.X - o.X, Y: v.Y - o.Y}
+}
+
+// DistSq returns the squared Euclidean distance, which keeps ordering exact
+func (v Vec2) DistSq(o Vec2) int64 {
+	d := v.Sub(o)
+	return d.X*d.X + d.Y*d.Y
+}
+
+func (v Vec2) Manhattan(o Vec2) int64 {
+	return Abs(v.X-o.X) + Abs(v.Y-o.Y)
+}
+
+func (v Vec2) Chebyshev(o Vec2) int64 {
+	return max(Abs(v.X-o.X), Abs(v.Y-o.Y))
+}
+
+func (v Vec3) Add(o Vec3) Vec3 {
+	return Vec3{X: v.X + o.X, Y: v.Y + o.Y, Z: v.Z + o.Z}
+}
+
+func (v Vec3) Sub(o Vec3) Vec3 {
+	return Vec3{X: v.X - o.X, Y: v.Y - o.Y, Z: v.Z - o.Z}
+}
+
+// DistSq returns the squared Euclidean distance, which keeps ordering exact
+func (v Vec3) DistSq(o Vec3) int64 {
+	d := v.Sub(o)
+	return d.X*d.X + d.Y*d.Y + d.Z*d.Z
+}
+
+func (v Vec3) Manhattan(o Vec3) int64 {
+	return Abs(v.X-o.X) + Abs(v.Y-o.Y) + Abs(v.Z-o.Z)
+}
+
+func (v Vec3) Chebyshev(o Vec3) int64 {
+	return max(Abs(v.X-o.X), Abs(v.Y-o.Y), Abs(v.Z-o.Z))
+}
+
+// Rect is an axis aligned rectangle including both corners
+type Rect struct {
+	Min Vec2
+	Max Vec2
+}
+
+// RectFrom returns the rectangle with a and b as opposite corners
+func RectFrom(a, b Vec2) Rect {
+	return Rect{
+		Min: Vec2{X: min(a.X, b.X), Y: min(a.Y, b.Y)},
+		Max: Vec2{X: max(a.X, b.X), Y: max(a.Y, b.Y)},
+	}
+}
+
+func (r Rect) Width() int64 {
+	return r.Max.X - r.Min.X + 1
+}
+
+func (r Rect) Height() int64 {
+	return r.Max.Y - r.Min.Y + 1
+}
+
+// Area counts the grid cells covered, so a single point has area 1
+func (r Rect) Area() int64 {
+	return r.Width() * r.Height()
+}
+
+func (r Rect) Contains(p Vec2) bool {
+	return p.X >= r.Min.X && p.X <= r.Max.X && p.Y >= r.Min.Y && p.Y <= r.Max.Y
+}
+
+// Box is an axis aligned box including both corners
+type Box struct {
+	Min Vec3
+	Max Vec3
+}
+
+func (b Box) Contains(p Vec3) bool {
+	return p.X >= b.Min.X && p.X <= b.Max.X &&
+		p.Y >= b.Min.Y && p.Y <= b.Max.Y &&
+		p.Z >= b.Min.Z && p.Z <= b.Max.Z
+}
+
+// Bounds returns the smallest Rect containing every point
+func Bounds(points []Vec2) Rect {
+	if len(points) == 0 {
+		return Rect{}
+	}
+	r := Rect{Min: points[0], Max: points[0]}
+	for _, p := range points[1:] {
+		r.Min.X = min(r.Min.X, p.X)
+		r.Min.Y = min(r.Min.Y, p.Y)
+		r.Max.X = max(r.Max.X, p.X)
+		r.Max.Y = max(r.Max.Y, p.Y)
+	}
+	return r
+}
+
+// Bounds3 returns the smallest Box containing every point
+func Bounds3(points []Vec3) Box {
+	if len(points) == 0 {
+		return Box{}
+	}
+	b := Box{Min: points[0], Max: points[0]}
+	for _, p := range points[1:] {
+		b.Min.X = min(b.Min.X, p.X)
+		b.Min.Y = min(b.Min.Y, p.Y)
+		b.Min.Z = min(b.Min.Z, p.Z)
+		b.Max.X = max(b.Max.X, p.X)
+		b.Max.Y = max(b.Max.Y, p.Y)
+		b.Max.Z = max(b.Max.Z, p.Z)
+	}
+	return b
+}
+
+// TwiceArea returns twice the signed area of the polygon using the
+// shoelace formula. It is positive for counter clockwise vertices (with Y
+// pointing up) and stays exact since it is never halved.
+func TwiceArea(poly []Vec2) int64 {
+	acc := int64(0)
+	for i, p := range poly {
+		q := poly[(i+1)%len(poly)]
+		acc += p.X*q.Y - q.X*p.Y
+	}
+	return acc
+}
+
+// Segment is a straight line between two points, both ends included
+type Segment struct {
+	A Vec2
+	B Vec2
+}
+
+func (s Segment) Horizontal() bool {
+	return s.A.Y == s.B.Y
+}
+
+func (s Segment) Vertical() bool {
+	return s.A.X == s.B.X
+}
+
+func (s Segment) Bounds() Rect {
+	return RectFrom(s.A, s.B)
+}
+
+// Contains reports whether p lies on the axis aligned segment
+func (s Segment) Contains(p Vec2) bool {
+	return s.Bounds().Contains(p)
+}
+
+// Intersects reports whether two axis aligned segments share any point,
+// including touching at an end.
+func Intersects(a, b Segment) bool {
+	ra, rb := a.Bounds(), b.Bounds()
+	return ra.Min.X <= rb.Max.X && rb.Min.X <= ra.Max.X &&
+		ra.Min.Y <= rb.Max.Y && rb.Min.Y <= ra.Max.Y
+}
+
+// Crosses reports whether a horizontal and a vertical segment cross in a
+// point that is strictly inside both, so touching ends do not count.
+// Parallel segments never cross.
+func Crosses(a, b Segment) bool {
+	if a.Vertical() && b.Horizontal() {
+		a, b = b, a
+	}
+	if !a.Horizontal() || !b.Vertical() || a.Vertical() || b.Horizontal() {
+		return false
+	}
+	ra, rb := a.Bounds(), b.Bounds()
+	return ra.Min.X < rb.Min.X && rb.Min.X < ra.Max.X &&
+		rb.Min.Y < ra.Min.Y && ra.Min.Y < rb.Max.Y
+}
+
+// OnBoundary reports whether p lies on an edge of the closed polygon
+func OnBoundary(poly []Vec2, p Vec2) bool {
+	for i, a := range poly {
+		if (Segment{A: a, B: poly[(i+1)%len(poly)]}).Contains(p) {
+			return true
+		}
+	}
+	return false
+}
+
+// InPolygon reports whether p is inside or on the boundary of a rectilinear
+// polygon given as ordered vertices. It casts a ray towards +X and counts
+// the vertical edges it crosses, using half open Y ranges so that vertices
+// are not counted twice.
+func InPolygon(poly []Vec2, p Vec2) bool {
+	if OnBoundary(poly, p) {
+		return true
+	}
+	inside := false
+	for i, a := range poly {
+		b := poly[(i+1)%len(poly)]
+		if a.X != b.X || a.X <= p.X {
+			continue
+		}
+		lo, hi := min(a.Y, b.Y), max(a.Y, b.Y)
+		if p.Y >= lo && p.Y < hi {
+			inside = !inside
+		}
+	}
+	return inside
+}
@@ -0,0 +1,70 @@
+package geom
+
+import "testing"
+
+func TestDistances(t *testing.T) {
+	a := Vec3{X: 162, Y: 817, Z: 812}
+	b := Vec3{X: 425, Y: 690, Z: 689}
+	if got := a.DistSq(b); got != 100427 {
+		t.Errorf("got %d, want 100427", got)
+	}
+	if got := a.Manhattan(b); got != 263+127+123 {
+		t.Errorf("got %d, want 513", got)
+	}
+	if got := a.Chebyshev(b); got != 263 {
+		t.Errorf("got %d, want 263", got)
+	}
+	if got := RectFrom(Vec2{X: 11, Y: 1}, Vec2{X: 2, Y: 5}).Area(); got != 50 {
+		t.Errorf("got %d, want 50", got)
+	}
+}
+
+// U shaped polygon, open at the top between x=3 and x=7
+var uShape = []Vec2{
+	{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 10, Y: 10}, {X: 7, Y: 10},
+	{X: 7, Y: 3}, {X: 3, Y: 3}, {X: 3, Y: 10}, {X: 0, Y: 10},
+}
+
+func TestTwiceArea(t *testing.T) {
+	// 10x10 square minus the 4x7 notch
+	if got := TwiceArea(uShape); got != 2*(100-28) {
+		t.Errorf("got %d, want %d", got, 2*(100-28))
+	}
+}
+
+func TestInPolygon(t *testing.T) {
+	tests := []struct {
+		p    Vec2
+		want bool
+	}{
+		{Vec2{X: 1, Y: 1}, true},
+		{Vec2{X: 5, Y: 2}, true},
+		{Vec2{X: 5, Y: 3}, true}, // on the bottom of the notch
+		{Vec2{X: 5, Y: 5}, false},
+		{Vec2{X: 3, Y: 10}, true},
+		{Vec2{X: 8, Y: 9}, true},
+		{Vec2{X: 11, Y: 5}, false},
+		{Vec2{X: -1, Y: 0}, false},
+	}
+	for _, tt := range tests {
+		if got := InPolygon(uShape, tt.p); got != tt.want {
+			t.Errorf("InPolygon(%v): got %t, want %t", tt.p, got, tt.want)
+		}
+	}
+}
+
+func TestSegments(t *testing.T) {
+	h := Segment{A: Vec2{X: 0, Y: 5}, B: Vec2{X: 10, Y: 5}}
+	v := Segment{A: Vec2{X: 4, Y: 0}, B: Vec2{X: 4, Y: 9}}
+	touch := Segment{A: Vec2{X: 10, Y: 5}, B: Vec2{X: 10, Y: 9}}
+
+	if !Crosses(h, v) || !Crosses(v, h) {
+		t.Errorf("expected segments to cross")
+	}
+	if Crosses(h, touch) {
+		t.Errorf("touching ends should not cross")
+	}
+	if !Intersects(h, touch) {
+		t.Errorf("touching ends should intersect")
+	}
+}
@@ -0,0 +1,137 @@
+package geom
+
+import (
+	"slices"
+
+	"aoc-2025/helpers"
+)
+
+const kdLeafSize = 8
+
+// KDTree indexes 3D points for nearest neighbour queries. Points are
+// referred to by their index in the slice given to NewKDTree.
+type KDTree struct {
+	points []Vec3
+	root   *kdNode
+}
+
+type kdNode struct {
+	bounds Box
+	left   *kdNode
+	right  *kdNode
+	idx    []int // only set on leaves
+}
+
+func NewKDTree(points []Vec3) *KDTree {
+	t := &KDTree{points: points}
+	if len(points) > 0 {
+		idx := make([]int, len(points))
+		for i := range idx {
+			idx[i] = i
+		}
+		t.root = t.build(idx)
+	}
+	return t
+}
+
+func (t *KDTree) build(idx []int) *kdNode {
+	pts := make([]Vec3, len(idx))
+	for i, j := range idx {
+		pts[i] = t.points[j]
+	}
+	node := &kdNode{bounds: Bounds3(pts)}
+	if len(idx) <= kdLeafSize {
+		node.idx = idx
+		return node
+	}
+
+	// Split on the axis with the largest spread
+	size := node.bounds.Max.Sub(node.bounds.Min)
+	axis := func(p Vec3) int64 { return p.X }
+	if size.Y >= size.X && size.Y >= size.Z {
+		axis = func(p Vec3) int64 { return p.Y }
+	} else if size.Z >= size.X && size.Z >= size.Y {
+		axis = func(p Vec3) int64 { return p.Z }
+	}
+	slices.SortFunc(idx, func(a, b int) int {
+		return int(min(max(axis(t.points[a])-axis(t.points[b]), -1), 1))
+	})
+
+	mid := len(idx) / 2
+	node.left = t.build(idx[:mid])
+	node.right = t.build(idx[mid:])
+	return node
+}
+
+// minDistSq is the squared distance from q to the closest point of the box
+func (b Box) minDistSq(q Vec3) int64 {
+	d := Vec3{
+		X: max(b.Min.X-q.X, 0, q.X-b.Max.X),
+		Y: max(b.Min.Y-q.Y, 0, q.Y-b.Max.Y),
+		Z: max(b.Min.Z-q.Z, 0, q.Z-b.Max.Z),
+	}
+	return d.X*d.X + d.Y*d.Y + d.Z*d.Z
+}
+
+type kdEntry struct {
+	dist  int64
+	node  *kdNode // nil for a point
+	point int
+}
+
+// Neighbours walks the points in increasing order of squared distance to q,
+// with ties broken by lower index. Points where skip returns true are left
+// out. The work done is proportional to how far the iterator is advanced.
+type Neighbours struct {
+	tree  *KDTree
+	q     Vec3
+	skip  func(int) bool
+	queue *helpers.Heap[kdEntry]
+}
+
+func (t *KDTree) Neighbours(q Vec3, skip func(int) bool) *Neighbours {
+	n := &Neighbours{
+		tree: t,
+		q:    q,
+		skip: skip,
+		// Nodes sort before points at the same distance, so a point is only
+		// returned once every node that could hold a tying point is opened
+		queue: helpers.NewHeap(func(a, b kdEntry) bool {
+			if a.dist != b.dist {
+				return a.dist < b.dist
+			}
+			if (a.node != nil) != (b.node != nil) {
+				return a.node != nil
+			}
+			return a.point < b.point
+		}),
+	}
+	if t.root != nil {
+		n.queue.Push(kdEntry{dist: t.root.bounds.minDistSq(q), node: t.root})
+	}
+	return n
+}
+
+// Next returns the next point index and its squared distance
+func (n *Neighbours) Next() (int, int64, bool) {
+	for {
+		e, ok := n.queue.Pop()
+		if !ok {
+			return 0, 0, false
+		}
+		if e.node == nil {
+			return e.point, e.dist, true
+		}
+		if e.node.idx != nil {
+			for _, j := range e.node.idx {
+				if n.skip == nil || !n.skip(j) {
+					n.queue.Push(kdEntry{dist: n.tree.points[j].DistSq(n.q), point: j})
+				}
+			}
+			continue
+		}
+		for _, child := range []*kdNode{e.node.left, e.node.right} {
+			n.queue.Push(kdEntry{dist: child.bounds.minDistSq(n.q), node: child})
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package geom
+
+import (
+	"math/rand/v2"
+	"testing"
+)
+
+func TestKDTreeNeighbours(t *testing.T) {
+	rng := rand.New(rand.NewPCG(1, 2))
+	points := make([]Vec3, 200)
+	for i := range points {
+		points[i] = Vec3{X: rng.Int64N(8), Y: rng.Int64N(8), Z: rng.Int64N(8)}
+	}
+	tree := NewKDTree(points)
+
+	q := Vec3{X: 3, Y: 4, Z: 5}
+	it := tree.Neighbours(q, func(j int) bool { return j%3 == 0 })
+	prevDist, prevIdx, seen := int64(-1), -1, 0
+	for {
+		j, d, ok := it.Next()
+		if !ok {
+			break
+		}
+		if j%3 == 0 {
+			t.Fatalf("skipped point %d was returned", j)
+		}
+		if d != points[j].DistSq(q) {
+			t.Fatalf("point %d: got distance %d, want %d", j, d, points[j].DistSq(q))
+		}
+		if d < prevDist || (d == prevDist && j < prevIdx) {
+			t.Fatalf("point %d at %d came after point %d at %d", j, d, prevIdx, prevDist)
+		}
+		prevDist, prevIdx = d, j
+		seen++
+	}
+	if want := len(points) - (len(points)+2)/3; seen != want {
+		t.Errorf("got %d points, want %d", seen, want)
+	}
+}
@@ -0,0 +1,213 @@
+package geom
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+)
+
+var (
+	ErrNotRectilinear   = errors.New("polygon is not rectilinear")
+	ErrSelfIntersecting = errors.New("polygon intersects itself")
+)
+
+// Rectilinear is a simple polygon with only horizontal and vertical edges,
+// filled in as grid cells: a cell is in the polygon when it is on an edge or
+// enclosed by them.
+//
+// Containment is answered on a compressed grid. Every distinct vertex X and
+// Y gets a column or row of its own, and each run of coordinates between two
+// neighbouring ones becomes a single column or row, since every cell in the
+// run is on the same side of the boundary. Even indices are vertex
+// coordinates and odd indices are the runs between them. A 2D prefix sum of
+// cells outside the polygon then checks any rectangle in O(1).
+type Rectilinear struct {
+	Vertices []Vec2
+
+	xs, ys []int64
+	w, h   int
+	// outside[r*(w+1)+c] counts the compressed cells above and left of (r, c)
+	// that are outside the polygon
+	outside []int
+}
+
+// NewRectilinear builds the polygon from its vertices in order, with an edge
+// from the last back to the first. Edges that aren't horizontal or vertical,
+// edges that double back, and edges that touch any edge other than their
+// neighbours are rejected.
+func NewRectilinear(vertices []Vec2) (*Rectilinear, error) {
+	if err := validateRectilinear(vertices); err != nil {
+		return nil, err
+	}
+
+	p := &Rectilinear{Vertices: vertices}
+	for _, v := range vertices {
+		p.xs = append(p.xs, v.X)
+		p.ys = append(p.ys, v.Y)
+	}
+	slices.Sort(p.xs)
+	slices.Sort(p.ys)
+	p.xs = slices.Compact(p.xs)
+	p.ys = slices.Compact(p.ys)
+	p.w, p.h = 2*len(p.xs)-1, 2*len(p.ys)-1
+	p.fill()
+	return p, nil
+}
+
+func validateRectilinear(vertices []Vec2) error {
+	n := len(vertices)
+	if n < 4 {
+		return fmt.Errorf("%w: need at least 4 vertices, got %d", ErrNotRectilinear, n)
+	}
+
+	edges := make([]Segment, n)
+	for i, a := range vertices {
+		edges[i] = Segment{A: a, B: vertices[(i+1)%n]}
+		e := edges[i]
+		if e.A == e.B {
+			return fmt.Errorf("%w: vertex %d repeats %v", ErrNotRectilinear, (i+1)%n, e.A)
+		}
+		if !e.Horizontal() && !e.Vertical() {
+			return fmt.Errorf("%w: edge %v to %v is diagonal", ErrNotRectilinear, e.A, e.B)
+		}
+	}
+
+	for i, e := range edges {
+		// Neighbouring edges share a vertex, but must not run back over
+		// each other
+		next := edges[(i+1)%n]
+		d1, d2 := direction(e), direction(next)
+		if d1.X*d2.X+d1.Y*d2.Y < 0 {
+			return fmt.Errorf("%w: edge doubles back at %v", ErrSelfIntersecting, e.B)
+		}
+
+		for j := i + 2; j < n; j++ {
+			if i == 0 && j == n-1 {
+				continue
+			}
+			if Intersects(e, edges[j]) {
+				return fmt.Errorf("%w: edge %v to %v touches edge %v to %v",
+					ErrSelfIntersecting, e.A, e.B, edges[j].A, edges[j].B)
+			}
+		}
+	}
+	return nil
+}
+
+// direction is the unit step along e. It is found by comparing the ends, as
+// subtracting them can overflow when coordinates span most of int64.
+func direction(e Segment) Vec2 {
+	return Vec2{int64(cmp.Compare(e.B.X, e.A.X)), int64(cmp.Compare(e.B.Y, e.A.Y))}
+}
+
+// fill marks the compressed cells on the boundary, works out which of the
+// rest are enclosed with a crossing count along each row, and builds the
+// prefix sums
+func (p *Rectilinear) fill() {
+	const (
+		unknown = iota
+		edge
+		empty // a run between neighbouring coordinates that holds no cells
+	)
+	cells := make([]byte, p.w*p.h)
+	for c := 1; c < p.w; c += 2 {
+		if p.xs[c/2+1]-1 == p.xs[c/2] {
+			for r := range p.h {
+				cells[r*p.w+c] = empty
+			}
+		}
+	}
+	for r := 1; r < p.h; r += 2 {
+		if p.ys[r/2+1]-1 == p.ys[r/2] {
+			for c := range p.w {
+				cells[r*p.w+c] = empty
+			}
+		}
+	}
+
+	// crossings[r*p.w+c] flips the inside state for every cell right of c
+	crossings := make([]bool, p.w*p.h)
+	for i, a := range p.Vertices {
+		b := p.Vertices[(i+1)%len(p.Vertices)]
+		c1, c2 := p.col(a.X), p.col(b.X)
+		r1, r2 := p.row(a.Y), p.row(b.Y)
+		for r := min(r1, r2); r <= max(r1, r2); r++ {
+			for c := min(c1, c2); c <= max(c1, c2); c++ {
+				cells[r*p.w+c] = edge
+			}
+		}
+
+		// A vertical edge is crossed by the rows in [low Y, high Y), so a
+		// vertex shared by two edges isn't counted twice
+		if c1 == c2 {
+			for r := min(r1, r2); r < max(r1, r2); r++ {
+				crossings[r*p.w+c1] = !crossings[r*p.w+c1]
+			}
+		}
+	}
+
+	stride := p.w + 1
+	p.outside = make([]int, stride*(p.h+1))
+	for r := range p.h {
+		inside := false
+		for c := range p.w {
+			bad := 0
+			if cells[r*p.w+c] == unknown && !inside {
+				bad = 1
+			}
+			if crossings[r*p.w+c] {
+				inside = !inside
+			}
+			p.outside[(r+1)*stride+c+1] = bad + p.outside[r*stride+c+1] +
+				p.outside[(r+1)*stride+c] - p.outside[r*stride+c]
+		}
+	}
+}
+
+// compress returns the compressed index of v, and false if v is outside the
+// range of the coordinates
+func compress(coords []int64, v int64) (int, bool) {
+	i, found := slices.BinarySearch(coords, v)
+	switch {
+	case found:
+		return 2 * i, true
+	case i == 0 || i == len(coords):
+		return 0, false
+	default:
+		return 2*i - 1, true
+	}
+}
+
+func (p *Rectilinear) col(x int64) int {
+	c, _ := compress(p.xs, x)
+	return c
+}
+
+func (p *Rectilinear) row(y int64) int {
+	r, _ := compress(p.ys, y)
+	return r
+}
+
+// ContainsRect reports whether every cell of r is in the polygon
+func (p *Rectilinear) ContainsRect(r Rect) bool {
+	c1, ok1 := compress(p.xs, r.Min.X)
+	c2, ok2 := compress(p.xs, r.Max.X)
+	r1, ok3 := compress(p.ys, r.Min.Y)
+	r2, ok4 := compress(p.ys, r.Max.Y)
+	if !ok1 || !ok2 || !ok3 || !ok4 {
+		return false
+	}
+	c2++
+	r2++
+
+	stride := p.w + 1
+	n := p.outside[r2*stride+c2] - p.outside[r1*stride+c2] -
+		p.outside[r2*stride+c1] + p.outside[r1*stride+c1]
+	return n == 0
+}
+
+// Contains reports whether the cell at v is in the polygon
+func (p *Rectilinear) Contains(v Vec2) bool {
+	return p.ContainsRect(Rect{Min: v, Max: v})
+}
@@ -0,0 +1,94 @@
+package geom
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+// ring is a square with a square hole, joined to the outside by a channel
+// along the bottom. Every row and column through the hole has cells of the
+// polygon on both sides of it.
+var ring = []Vec2{
+	{0, 0}, {8, 0}, {8, 5}, {5, 5}, {5, 15}, {15, 15},
+	{15, 5}, {12, 5}, {12, 0}, {20, 0}, {20, 20}, {0, 20},
+}
+
+var shapes = map[string][]Vec2{
+	"ring": ring,
+	"u":    {{0, 0}, {9, 0}, {9, 9}, {6, 9}, {6, 3}, {3, 3}, {3, 9}, {0, 9}},
+	// Runs of exactly one coordinate between vertices, and a straight vertex
+	"steps": {{0, 0}, {2, 0}, {4, 0}, {4, 1}, {5, 1}, {5, 3}, {1, 3}, {1, 2}, {0, 2}},
+}
+
+func TestRectilinearContainsRect(t *testing.T) {
+	for name, poly := range shapes {
+		p, err := NewRectilinear(poly)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+
+		b := Bounds(poly)
+		lo, hi := b.Min.X-1, b.Max.X+1
+		in := func(x, y int64) bool { return InPolygon(poly, Vec2{x, y}) }
+		for x1 := lo; x1 <= hi; x1++ {
+			for x2 := x1; x2 <= hi; x2++ {
+				for y1 := lo; y1 <= hi; y1++ {
+					for y2 := y1; y2 <= hi; y2++ {
+						want := true
+						for x := x1; x <= x2 && want; x++ {
+							for y := y1; y <= y2 && want; y++ {
+								want = in(x, y)
+							}
+						}
+						r := Rect{Min: Vec2{x1, y1}, Max: Vec2{x2, y2}}
+						if got := p.ContainsRect(r); got != want {
+							t.Fatalf("%s: ContainsRect(%v) = %v, want %v", name, r, got, want)
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestRectilinearInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		poly []Vec2
+		want error
+	}{
+		{"triangle", []Vec2{{0, 0}, {4, 0}, {0, 4}}, ErrNotRectilinear},
+		{"diagonal", []Vec2{{0, 0}, {4, 0}, {4, 4}, {1, 4}, {0, 3}}, ErrNotRectilinear},
+		{"repeated vertex", []Vec2{{0, 0}, {4, 0}, {4, 0}, {4, 4}, {0, 4}}, ErrNotRectilinear},
+		{"bow tie", []Vec2{{0, 0}, {4, 0}, {4, 4}, {2, 4}, {2, -2}, {0, -2}}, ErrSelfIntersecting},
+		{"doubles back", []Vec2{{0, 0}, {4, 0}, {2, 0}, {2, 4}, {0, 4}}, ErrSelfIntersecting},
+		{"touching", []Vec2{{0, 0}, {4, 0}, {4, 4}, {2, 4}, {2, 0}, {1, 0}, {1, 4}, {0, 4}}, ErrSelfIntersecting},
+	}
+	for _, tt := range tests {
+		if _, err := NewRectilinear(tt.poly); !errors.Is(err, tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestRectilinearExtremes(t *testing.T) {
+	// Edges spanning most of int64, with a vertex in the middle of a
+	// straight run, must not be mistaken for an edge doubling back
+	band := []Vec2{
+		{math.MinInt64, 0}, {0, 0}, {math.MaxInt64, 0},
+		{math.MaxInt64, 1}, {math.MinInt64, 1},
+	}
+	p, err := NewRectilinear(band)
+	if err != nil {
+		t.Fatalf("got %v, want a valid polygon", err)
+	}
+	if !p.ContainsRect(RectFrom(band[0], band[3])) {
+		t.Error("expected the whole band to be inside")
+	}
+
+	back := []Vec2{{math.MinInt64, 0}, {math.MaxInt64, 0}, {0, 0}, {0, 1}, {math.MinInt64, 1}}
+	if _, err := NewRectilinear(back); !errors.Is(err, ErrSelfIntersecting) {
+		t.Errorf("got %v, want %v", err, ErrSelfIntersecting)
+	}
+}
@@ -0,0 +1,139 @@
+// Package gf2 solves linear systems over GF(2), where addition is XOR.
+package gf2
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+
+	"aoc-2025/helpers"
+)
+
+// ErrInconsistent is returned when a system has no solution
+var ErrInconsistent = errors.New("gf2: system has no solution")
+
+// MaxFree limits how many free variables MinWeight will enumerate
+const MaxFree = 30
+
+// System is a set of equations a·x = b. Each equation is stored as a bitset
+// of coefficients with the right hand side in the bit after the last
+// variable.
+type System struct {
+	vars int
+	rows []*helpers.BitSet
+}
+
+func NewSystem(vars int) *System {
+	return &System{vars: vars}
+}
+
+// AddEquation adds sum(x[i] for i in coeffs) = rhs
+func (s *System) AddEquation(coeffs *helpers.BitSet, rhs bool) {
+	row := helpers.NewBitSet(s.vars + 1)
+	for i := range coeffs.Bits() {
+		if i < s.vars {
+			row.Set(i)
+		}
+	}
+	if rhs {
+		row.Set(s.vars)
+	}
+	s.rows = append(s.rows, row)
+}
+
+// Solution describes every solution of a system as Particular xor any
+// combination of the Nullspace vectors.
+type Solution struct {
+	Particular *helpers.BitSet
+	Nullspace  []*helpers.BitSet
+	Pivots     []int // pivot variable of each reduced row
+	Free       []int // variables not fixed by a pivot
+}
+
+// Solve row reduces the system to reduced row echelon form. It returns
+// ErrInconsistent if some equation reduces to 0 = 1.
+func (s *System) Solve() (*Solution, error) {
+	rows := make([]*helpers.BitSet, len(s.rows))
+	for i, row := range s.rows {
+		rows[i] = row.Clone()
+	}
+
+	var pivots []int
+	isPivot := make([]bool, s.vars)
+	r := 0
+	for c := 0; c < s.vars && r < len(rows); c++ {
+		found := -1
+		for i := r; i < len(rows); i++ {
+			if rows[i].Test(c) {
+				found = i
+				break
+			}
+		}
+		if found == -1 {
+			continue
+		}
+		rows[r], rows[found] = rows[found], rows[r]
+
+		// Clear the column everywhere else, above and below
+		for i := range rows {
+			if i != r && rows[i].Test(c) {
+				rows[i].Xor(rows[r])
+			}
+		}
+		pivots = append(pivots, c)
+		isPivot[c] = true
+		r++
+	}
+
+	for _, row := range rows[r:] {
+		if row.Test(s.vars) {
+			return nil, ErrInconsistent
+		}
+	}
+
+	sol := &Solution{Particular: helpers.NewBitSet(s.vars), Pivots: pivots}
+	for k, c := range pivots {
+		if rows[k].Test(s.vars) {
+			sol.Particular.Set(c)
+		}
+	}
+
+	// One basis vector per free variable: set it and solve for the pivots
+	for f := range s.vars {
+		if isPivot[f] {
+			continue
+		}
+		sol.Free = append(sol.Free, f)
+		v := helpers.NewBitSet(s.vars).Set(f)
+		for k, c := range pivots {
+			if rows[k].Test(f) {
+				v.Set(c)
+			}
+		}
+		sol.Nullspace = append(sol.Nullspace, v)
+	}
+
+	return sol, nil
+}
+
+// MinWeight returns the solution with the fewest variables set. It walks
+// every combination of nullspace vectors in Gray code order, so each step
+// is a single XOR, and fails if there are more than MaxFree of them.
+func (sol *Solution) MinWeight() (*helpers.BitSet, error) {
+	k := len(sol.Nullspace)
+	if k > MaxFree {
+		return nil, fmt.Errorf("gf2: %d free variables is too many to enumerate", k)
+	}
+
+	curr := sol.Particular.Clone()
+	best := curr.Clone()
+	bestCount := best.Count()
+	for i := uint64(1); i < 1<<k; i++ {
+		curr.Xor(sol.Nullspace[bits.TrailingZeros64(i)])
+		if c := curr.Count(); c < bestCount {
+			best = curr.Clone()
+			bestCount = c
+		}
+	}
+	return best, nil
+}
@@ -0,0 +1,76 @@
+package gf2
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"aoc-2025/helpers"
+)
+
+// buttons (3) (1,3) (2) (2,3) (0,2) (0,1) and target .##.
+func exampleSystem() *System {
+	buttons := [][]int{{3}, {1, 3}, {2}, {2, 3}, {0, 2}, {0, 1}}
+	target := []bool{false, true, true, false}
+
+	s := NewSystem(len(buttons))
+	for light, on := range target {
+		coeffs := helpers.NewBitSet(len(buttons))
+		for i, btn := range buttons {
+			if slices.Contains(btn, light) {
+				coeffs.Set(i)
+			}
+		}
+		s.AddEquation(coeffs, on)
+	}
+	return s
+}
+
+func TestSolve(t *testing.T) {
+	sol, err := exampleSystem().Solve()
+	if err != nil {
+		t.Fatalf("Solve failed: %v", err)
+	}
+	if len(sol.Pivots) != 4 || len(sol.Free) != 2 {
+		t.Errorf("got %d pivots and %d free, want 4 and 2", len(sol.Pivots), len(sol.Free))
+	}
+
+	best, err := sol.MinWeight()
+	if err != nil {
+		t.Fatalf("MinWeight failed: %v", err)
+	}
+	if best.Count() != 2 {
+		t.Errorf("got %d presses, want 2", best.Count())
+	}
+
+	// Any solution xor'd with a nullspace vector is still a solution
+	for _, v := range sol.Nullspace {
+		check := sol.Particular.Clone().Xor(v)
+		if !satisfies(exampleSystem(), check) {
+			t.Errorf("%s is not a solution", check)
+		}
+	}
+	if !satisfies(exampleSystem(), best) {
+		t.Errorf("%s is not a solution", best)
+	}
+}
+
+func satisfies(s *System, x *helpers.BitSet) bool {
+	for _, row := range s.rows {
+		lhs := row.Clone().Clear(s.vars).And(x).Count() % 2
+		if (lhs == 1) != row.Test(s.vars) {
+			return false
+		}
+	}
+	return true
+}
+
+func TestInconsistent(t *testing.T) {
+	s := NewSystem(2)
+	s.AddEquation(helpers.BitSetOf(2, 0, 1), true)
+	s.AddEquation(helpers.BitSetOf(2, 0, 1), false)
+
+	if _, err := s.Solve(); !errors.Is(err, ErrInconsistent) {
+		t.Errorf("got %v, want ErrInconsistent", err)
+	}
+}
@@ -0,0 +1,443 @@
+// Package graph provides a directed graph keyed by any comparable type.
+package graph
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"aoc-2025/helpers"
+)
+
+// Graph is an adjacency list digraph. Keys are mapped to dense ids in the
+// order they are first seen, so iteration order is stable.
+type Graph[K comparable] struct {
+	index map[K]int
+	keys  []K
+	out   [][]int
+}
+
+func New[K comparable]() *Graph[K] {
+	return &Graph[K]{index: make(map[K]int)}
+}
+
+// Parse builds a graph from lines like "aaa: bbb ccc". Blank lines are skipped.
+func Parse(lines []string) (*Graph[string], error) {
+	g := New[string]()
+	for i, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		key, rest, ok := strings.Cut(line, ":")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("line %d: expected \"key: children\", got %q", i+1, line)
+		}
+		g.AddNode(key)
+		for _, child := range strings.Fields(rest) {
+			g.AddEdge(key, child)
+		}
+	}
+	return g, nil
+}
+
+// AddNode adds k if it is missing and returns its id.
+func (g *Graph[K]) AddNode(k K) int {
+	if id, exists := g.index[k]; exists {
+		return id
+	}
+	id := len(g.keys)
+	g.index[k] = id
+	g.keys = append(g.keys, k)
+	g.out = append(g.out, nil)
+	return id
+}
+
+// AddEdge adds an edge from -> to, creating both nodes if needed.
+func (g *Graph[K]) AddEdge(from, to K) {
+	f := g.AddNode(from)
+	t := g.AddNode(to)
+	g.out[f] = append(g.out[f], t)
+}
+
+func (g *Graph[K]) Has(k K) bool {
+	_, exists := g.index[k]
+	return exists
+}
+
+func (g *Graph[K]) Len() int {
+	return len(g.keys)
+}
+
+// Nodes returns all keys in insertion order.
+func (g *Graph[K]) Nodes() []K {
+	return append([]K(nil), g.keys...)
+}
+
+// Children returns the direct successors of k.
+func (g *Graph[K]) Children(k K) []K {
+	id, exists := g.index[k]
+	if !exists {
+		return nil
+	}
+	return g.toKeys(g.out[id])
+}
+
+func (g *Graph[K]) toKeys(ids []int) []K {
+	keys := make([]K, len(ids))
+	for i, id := range ids {
+		keys[i] = g.keys[id]
+	}
+	return keys
+}
+
+// CycleError is returned when an operation needs a DAG. Cycle lists the
+// nodes in order, with the first node repeated at the end.
+type CycleError[K comparable] struct {
+	Cycle []K
+}
+
+func (e *CycleError[K]) Error() string {
+	parts := make([]string, len(e.Cycle))
+	for i, k := range e.Cycle {
+		parts[i] = fmt.Sprint(k)
+	}
+	return "graph: cycle detected: " + strings.Join(parts, " -> ")
+}
+
+const (
+	white = iota
+	grey
+	black
+)
+
+type frame struct {
+	node int
+	next int
+}
+
+// topo runs an iterative DFS from the given roots, only following nodes for
+// which keep returns true. It returns the visited nodes in topological order
+// or the first cycle found.
+func (g *Graph[K]) topo(roots []int, keep func(int) bool) ([]int, []int) {
+	color := make([]uint8, len(g.keys))
+	post := make([]int, 0, len(g.keys))
+	var stack []frame
+
+	for _, root := range roots {
+		if color[root] != white || !keep(root) {
+			continue
+		}
+		color[root] = grey
+		stack = append(stack, frame{node: root})
+		for len(stack) > 0 {
+			top := &stack[len(stack)-1]
+			if top.next == len(g.out[top.node]) {
+				color[top.node] = black
+				post = append(post, top.node)
+				stack = stack[:len(stack)-1]
+				continue
+			}
+			child := g.out[top.node][top.next]
+			top.next++
+			if !keep(child) {
+				continue
+			}
+			switch color[child] {
+			case white:
+				color[child] = grey
+				stack = append(stack, frame{node: child})
+			case grey:
+				// Walk back down the stack to where the cycle starts
+				i := len(stack) - 1
+				for stack[i].node != child {
+					i--
+				}
+				cycle := make([]int, 0, len(stack)-i+1)
+				for _, f := range stack[i:] {
+					cycle = append(cycle, f.node)
+				}
+				return nil, append(cycle, child)
+			}
+		}
+	}
+
+	// Reverse post order is a topological order
+	for i, j := 0, len(post)-1; i < j; i, j = i+1, j-1 {
+		post[i], post[j] = post[j], post[i]
+	}
+	return post, nil
+}
+
+func (g *Graph[K]) allIDs() []int {
+	ids := make([]int, len(g.keys))
+	for i := range ids {
+		ids[i] = i
+	}
+	return ids
+}
+
+func all(int) bool { return true }
+
+// TopoSort returns every node ordered so that edges point forward. It
+// returns a *CycleError if the graph is not acyclic.
+func (g *Graph[K]) TopoSort() ([]K, error) {
+	order, cycle := g.topo(g.allIDs(), all)
+	if cycle != nil {
+		return nil, &CycleError[K]{Cycle: g.toKeys(cycle)}
+	}
+	return g.toKeys(order), nil
+}
+
+// FindCycle returns one cycle in the graph, or nil if there is none.
+func (g *Graph[K]) FindCycle() []K {
+	_, cycle := g.topo(g.allIDs(), all)
+	if cycle == nil {
+		return nil
+	}
+	return g.toKeys(cycle)
+}
+
+// reach marks the nodes reachable from the given ones without passing
+// through a node in avoid
+func (g *Graph[K]) reach(from []int, edges [][]int, avoid map[int]bool) []bool {
+	seen := make([]bool, len(g.keys))
+	stack := append([]int(nil), from...)
+	for _, id := range from {
+		seen[id] = true
+	}
+	for len(stack) > 0 {
+		curr := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for _, next := range edges[curr] {
+			if !seen[next] && !avoid[next] {
+				seen[next] = true
+				stack = append(stack, next)
+			}
+		}
+	}
+	return seen
+}
+
+func (g *Graph[K]) reverse() [][]int {
+	in := make([][]int, len(g.keys))
+	for from, children := range g.out {
+		for _, to := range children {
+			in[to] = append(in[to], from)
+		}
+	}
+	return in
+}
+
+// Reachable returns every node reachable from k, including k itself.
+func (g *Graph[K]) Reachable(k K) []K {
+	id, exists := g.index[k]
+	if !exists {
+		return nil
+	}
+	var res []K
+	for i, ok := range g.reach([]int{id}, g.out, nil) {
+		if ok {
+			res = append(res, g.keys[i])
+		}
+	}
+	return res
+}
+
+// CanReach reports whether there is a path from -> to.
+func (g *Graph[K]) CanReach(from, to K) bool {
+	f, ok := g.index[from]
+	if !ok {
+		return false
+	}
+	t, ok := g.index[to]
+	if !ok {
+		return false
+	}
+	return g.reach([]int{f}, g.out, nil)[t]
+}
+
+// SCCs returns the strongly connected components using an iterative version
+// of Tarjan's algorithm. Components come out in reverse topological order.
+func (g *Graph[K]) SCCs() [][]K {
+	n := len(g.keys)
+	index := make([]int, n)
+	low := make([]int, n)
+	onStack := make([]bool, n)
+	for i := range index {
+		index[i] = -1
+	}
+
+	var comps [][]K
+	var sccStack []int
+	var stack []frame
+	counter := 0
+
+	for root := range n {
+		if index[root] != -1 {
+			continue
+		}
+		stack = append(stack, frame{node: root})
+		index[root], low[root] = counter, counter
+		counter++
+		sccStack = append(sccStack, root)
+		onStack[root] = true
+
+		for len(stack) > 0 {
+			top := &stack[len(stack)-1]
+			v := top.node
+			if top.next < len(g.out[v]) {
+				w := g.out[v][top.next]
+				top.next++
+				if index[w] == -1 {
+					index[w], low[w] = counter, counter
+					counter++
+					sccStack = append(sccStack, w)
+					onStack[w] = true
+					stack = append(stack, frame{node: w})
+				} else if onStack[w] {
+					low[v] = min(low[v], index[w])
+				}
+				continue
+			}
+
+			stack = stack[:len(stack)-1]
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1].node
+				low[parent] = min(low[parent], low[v])
+			}
+			if low[v] == index[v] {
+				var comp []K
+				for {
+					w := sccStack[len(sccStack)-1]
+					sccStack = sccStack[:len(sccStack)-1]
+					onStack[w] = false
+					comp = append(comp, g.keys[w])
+					if w == v {
+						break
+					}
+				}
+				comps = append(comps, comp)
+			}
+		}
+	}
+	return comps
+}
+
+// MaxWaypoints is the most via nodes CountPaths and CountPathsBig accept.
+// Each node keeps a count per subset of waypoints seen on the way to it, so
+// the work grows as 2^len(via) in the worst case.
+const MaxWaypoints = 20
+
+// CountPaths counts the distinct paths from -> to that pass through every
+// node in via, in any order. It is CountPathsBig for counts that fit in an
+// int64, and returns an error for those that don't.
+func (g *Graph[K]) CountPaths(from, to K, via ...K) (int64, error) {
+	n, err := g.CountPathsBig(from, to, via, nil)
+	if err != nil {
+		return 0, err
+	}
+	if !n.IsInt64() {
+		return 0, fmt.Errorf("graph: %s paths do not fit in an int64", n)
+	}
+	return n.Int64(), nil
+}
+
+// CountPathsBig counts the distinct paths from -> to that pass through every
+// node in via, in any order, and through none of the nodes in avoid. Only
+// nodes that lie on some such path are considered, and a cycle among them is
+// returned as a *CycleError since the count would be infinite. Counts are
+// kept as int64 until they overflow. At most MaxWaypoints via nodes are
+// allowed, and only the waypoint subsets that actually occur at a node are
+// stored.
+func (g *Graph[K]) CountPathsBig(from, to K, via, avoid []K) (*big.Int, error) {
+	if len(via) > MaxWaypoints {
+		return nil, fmt.Errorf("graph: too many waypoints: %d, at most %d", len(via), MaxWaypoints)
+	}
+	f, ok := g.index[from]
+	if !ok {
+		return new(big.Int), nil
+	}
+	t, ok := g.index[to]
+	if !ok {
+		return new(big.Int), nil
+	}
+
+	bits := make(map[int]int, len(via))
+	for i, k := range via {
+		id, exists := g.index[k]
+		if !exists {
+			return new(big.Int), nil
+		}
+		bits[id] |= 1 << i
+	}
+	full := 1<<len(via) - 1
+
+	avoided := make(map[int]bool, len(avoid))
+	for _, k := range avoid {
+		if id, exists := g.index[k]; exists {
+			avoided[id] = true
+		}
+	}
+	if avoided[f] || avoided[t] {
+		return new(big.Int), nil
+	}
+
+	forward := g.reach([]int{f}, g.out, avoided)
+	backward := g.reach([]int{t}, g.reverse(), avoided)
+	if !forward[t] {
+		return new(big.Int), nil
+	}
+	relevant := func(id int) bool { return forward[id] && backward[id] }
+
+	order, cycle := g.topo([]int{f}, relevant)
+	if cycle != nil {
+		return nil, &CycleError[K]{Cycle: g.toKeys(cycle)}
+	}
+
+	// counts[id][mask] is the number of paths from -> id that have seen
+	// exactly the waypoints in mask; masks no path produces are never stored
+	counts := make(map[int]map[int]helpers.Accumulator, len(order))
+	for _, id := range order {
+		counts[id] = make(map[int]helpers.Accumulator)
+	}
+	counts[f][bits[f]] = helpers.NewAccumulator(1)
+
+	for _, id := range order {
+		if id == t {
+			continue
+		}
+		curr := counts[id]
+		for _, child := range g.out[id] {
+			next, exists := counts[child]
+			if !exists {
+				continue
+			}
+			for mask, c := range curr {
+				sum := next[mask|bits[child]]
+				sum.AddAccumulator(c)
+				next[mask|bits[child]] = sum
+			}
+		}
+		delete(counts, id)
+	}
+
+	return counts[t][full].Big(), nil
+}
+
+// WriteDOT writes the graph in Graphviz DOT format.
+func (g *Graph[K]) WriteDOT(w io.Writer, name string) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "digraph %s {\n", strconv.Quote(name))
+	for id, k := range g.keys {
+		fmt.Fprintf(bw, "\t%s;\n", strconv.Quote(fmt.Sprint(k)))
+		for _, child := range g.out[id] {
+			fmt.Fprintf(bw, "\t%s -> %s;\n", strconv.Quote(fmt.Sprint(k)), strconv.Quote(fmt.Sprint(g.keys[child])))
+		}
+	}
+	fmt.Fprintln(bw, "}")
+	return bw.Flush()
+}
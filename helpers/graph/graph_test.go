@@ -0,0 +1,163 @@
+package graph
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+var example = []string{
+	"svr: aaa bbb",
+	"aaa: fft",
+	"fft: ccc",
+	"bbb: tty",
+	"tty: ccc",
+	"ccc: ddd eee",
+	"ddd: hub",
+	"hub: fff",
+	"eee: dac",
+	"dac: fff",
+	"fff: ggg hhh",
+	"ggg: out",
+	"hhh: out",
+}
+
+func TestCountPaths(t *testing.T) {
+	g, err := Parse(example)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	tests := []struct {
+		via  []string
+		want int64
+	}{
+		{nil, 8},
+		{[]string{"dac"}, 4},
+		{[]string{"dac", "fft"}, 2},
+		{[]string{"fft", "dac"}, 2},
+		{[]string{"hub", "dac"}, 0},
+	}
+	for _, tt := range tests {
+		got, err := g.CountPaths("svr", "out", tt.via...)
+		if err != nil {
+			t.Fatalf("CountPaths(%v) failed: %v", tt.via, err)
+		}
+		if got != tt.want {
+			t.Errorf("CountPaths(%v): got %d, want %d", tt.via, got, tt.want)
+		}
+	}
+}
+
+func TestCountPathsBig(t *testing.T) {
+	g, err := Parse(example)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	got, err := g.CountPathsBig("svr", "out", []string{"fft"}, []string{"dac"})
+	if err != nil || got.Int64() != 2 {
+		t.Errorf("got %v, %v, want 2 paths through fft that skip dac", got, err)
+	}
+
+	// A chain of 70 diamonds doubles the count 70 times
+	chain := New[int]()
+	for i := 0; i < 70; i++ {
+		a, b, next := 3*i, 3*i+1, 3*i+3
+		chain.AddEdge(a, a+1)
+		chain.AddEdge(a, a+2)
+		chain.AddEdge(b, next)
+		chain.AddEdge(b+1, next)
+	}
+	got, err = chain.CountPathsBig(0, 210, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := new(big.Int).Lsh(big.NewInt(1), 70); got.Cmp(want) != 0 {
+		t.Errorf("got %s, want %s", got, want)
+	}
+	if _, err := chain.CountPaths(0, 210); err == nil {
+		t.Error("expected CountPaths to fail when the count overflows")
+	}
+
+	// Every diamond top as a waypoint: the limit is accepted, one more is not
+	var via []int
+	for i := 0; i < MaxWaypoints; i++ {
+		via = append(via, 3*i)
+	}
+	if got, err := chain.CountPaths(0, 186, via...); err != nil || got != 1<<62 {
+		t.Errorf("%d waypoints: got %d, %v, want %d", len(via), got, err, int64(1)<<62)
+	}
+	if _, err := chain.CountPaths(0, 186, append(via, 3*MaxWaypoints)...); err == nil {
+		t.Errorf("expected an error for %d waypoints", len(via)+1)
+	}
+}
+
+func TestCycle(t *testing.T) {
+	g, err := Parse([]string{"a: b", "b: c", "c: a d", "d: out"})
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	_, err = g.TopoSort()
+	var cycleErr *CycleError[string]
+	if !errors.As(err, &cycleErr) {
+		t.Fatalf("expected CycleError, got %v", err)
+	}
+	if got := strings.Join(cycleErr.Cycle, " "); got != "a b c a" {
+		t.Errorf("got cycle %s, want a b c a", got)
+	}
+
+	if _, err := g.CountPaths("a", "out"); err == nil {
+		t.Errorf("expected CountPaths to fail on a cycle")
+	}
+	if got, err := g.CountPaths("d", "out"); err != nil || got != 1 {
+		t.Errorf("got %d, %v, want 1 for path outside the cycle", got, err)
+	}
+
+	sccs := g.SCCs()
+	if len(sccs) != 3 {
+		t.Errorf("got %d components, want 3", len(sccs))
+	}
+}
+
+func TestTopoSortAndReach(t *testing.T) {
+	g, _ := Parse(example)
+	order, err := g.TopoSort()
+	if err != nil {
+		t.Fatalf("TopoSort failed: %v", err)
+	}
+	pos := make(map[string]int)
+	for i, k := range order {
+		pos[k] = i
+	}
+	for _, k := range g.Nodes() {
+		for _, child := range g.Children(k) {
+			if pos[k] >= pos[child] {
+				t.Errorf("%s sorted after %s", k, child)
+			}
+		}
+	}
+
+	if !g.CanReach("aaa", "dac") {
+		t.Errorf("expected aaa to reach dac")
+	}
+	if g.CanReach("dac", "aaa") {
+		t.Errorf("expected dac not to reach aaa")
+	}
+	if got := len(g.Reachable("fff")); got != 4 {
+		t.Errorf("got %d reachable from fff, want 4", got)
+	}
+}
+
+func TestWriteDOT(t *testing.T) {
+	g, _ := Parse([]string{"a: b"})
+	var sb strings.Builder
+	if err := g.WriteDOT(&sb, "test"); err != nil {
+		t.Fatalf("WriteDOT failed: %v", err)
+	}
+	want := "digraph \"test\" {\n\t\"a\";\n\t\"a\" -> \"b\";\n\t\"b\";\n}\n"
+	if sb.String() != want {
+		t.Errorf("got %q, want %q", sb.String(), want)
+	}
+}
@@ -0,0 +1,21 @@
+// Package inputtest writes puzzle inputs for tests that need one the
+// input_test.txt files don't cover.
+package inputtest
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// Write saves lines as an input file in a temporary directory that is
+// removed when the test ends, and returns its path
+func Write(t testing.TB, lines ...string) string {
+	t.Helper()
+	input := filepath.Join(t.TempDir(), "input.txt")
+	if err := os.WriteFile(input, []byte(strings.Join(lines, "\n")+"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return input
+}
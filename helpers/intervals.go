@@ -0,0 +1,205 @@
+package helpers
+
+import (
+	"iter"
+	"math"
+	"slices"
+	"sort"
+)
+
+// Interval is an inclusive range [Start, End]
+type Interval struct {
+	Start int64
+	End   int64
+}
+
+func (i Interval) Len() int64 {
+	return i.End - i.Start + 1
+}
+
+// IntervalSet is a set of int64 values stored as sorted, disjoint, non
+// adjacent inclusive intervals.
+type IntervalSet struct {
+	spans []Interval
+}
+
+// NewIntervalSet builds a set from possibly overlapping intervals in
+// O(n log n). Intervals with End < Start are ignored.
+func NewIntervalSet(intervals ...Interval) *IntervalSet {
+	spans := make([]Interval, 0, len(intervals))
+	for _, iv := range intervals {
+		if iv.End >= iv.Start {
+			spans = append(spans, iv)
+		}
+	}
+	slices.SortFunc(spans, func(a, b Interval) int {
+		if a.Start < b.Start {
+			return -1
+		}
+		if a.Start > b.Start {
+			return 1
+		}
+		return 0
+	})
+
+	var merged []Interval
+	for _, iv := range spans {
+		if len(merged) == 0 {
+			merged = append(merged, iv)
+			continue
+		}
+
+		last := &merged[len(merged)-1]
+		// Overlapping or directly next to the last merged interval
+		if iv.Start <= inc(last.End) {
+			if iv.End > last.End {
+				last.End = iv.End
+			}
+		} else {
+			merged = append(merged, iv)
+		}
+	}
+	return &IntervalSet{spans: merged}
+}
+
+// inc and dec step by one without wrapping around at the int64 limits
+func inc(x int64) int64 {
+	if x == math.MaxInt64 {
+		return x
+	}
+	return x + 1
+}
+
+func dec(x int64) int64 {
+	if x == math.MinInt64 {
+		return x
+	}
+	return x - 1
+}
+
+// firstEndingAtOrAfter returns the index of the first span with End >= x
+func (s *IntervalSet) firstEndingAtOrAfter(x int64) int {
+	return sort.Search(len(s.spans), func(i int) bool { return s.spans[i].End >= x })
+}
+
+// firstStartingAfter returns the index of the first span with Start > x
+func (s *IntervalSet) firstStartingAfter(x int64) int {
+	return sort.Search(len(s.spans), func(i int) bool { return s.spans[i].Start > x })
+}
+
+// Add inserts [start, end], merging with any overlapping or adjacent spans
+func (s *IntervalSet) Add(start, end int64) {
+	if end < start {
+		return
+	}
+	lo := s.firstEndingAtOrAfter(dec(start))
+	hi := s.firstStartingAfter(inc(end))
+
+	merged := Interval{Start: start, End: end}
+	if lo < hi {
+		merged.Start = min(start, s.spans[lo].Start)
+		merged.End = max(end, s.spans[hi-1].End)
+	}
+	s.spans = slices.Replace(s.spans, lo, hi, merged)
+}
+
+// Remove deletes [start, end] from the set, splitting spans as needed
+func (s *IntervalSet) Remove(start, end int64) {
+	if end < start {
+		return
+	}
+	lo := s.firstEndingAtOrAfter(start)
+	hi := s.firstStartingAfter(end)
+	if lo >= hi {
+		return
+	}
+
+	var keep []Interval
+	if first := s.spans[lo]; first.Start < start {
+		keep = append(keep, Interval{Start: first.Start, End: start - 1})
+	}
+	if last := s.spans[hi-1]; last.End > end {
+		keep = append(keep, Interval{Start: end + 1, End: last.End})
+	}
+	s.spans = slices.Replace(s.spans, lo, hi, keep...)
+}
+
+// Contains reports whether x is in the set in O(log n)
+func (s *IntervalSet) Contains(x int64) bool {
+	i := s.firstEndingAtOrAfter(x)
+	return i < len(s.spans) && s.spans[i].Start <= x
+}
+
+// Union returns a new set with the values in either s or other
+func (s *IntervalSet) Union(other *IntervalSet) *IntervalSet {
+	return NewIntervalSet(append(slices.Clone(s.spans), other.spans...)...)
+}
+
+// Intersect returns a new set with the values in both s and other
+func (s *IntervalSet) Intersect(other *IntervalSet) *IntervalSet {
+	res := &IntervalSet{}
+	i, j := 0, 0
+	for i < len(s.spans) && j < len(other.spans) {
+		a, b := s.spans[i], other.spans[j]
+		start := max(a.Start, b.Start)
+		end := min(a.End, b.End)
+		if start <= end {
+			res.spans = append(res.spans, Interval{Start: start, End: end})
+		}
+		if a.End < b.End {
+			i++
+		} else {
+			j++
+		}
+	}
+	return res
+}
+
+// Complement returns the values in [lo, hi] that are not in s
+func (s *IntervalSet) Complement(lo, hi int64) *IntervalSet {
+	res := &IntervalSet{}
+	if hi < lo {
+		return res
+	}
+	next := lo
+	for i := s.firstEndingAtOrAfter(lo); i < len(s.spans) && s.spans[i].Start <= hi; i++ {
+		iv := s.spans[i]
+		if iv.Start > next {
+			res.spans = append(res.spans, Interval{Start: next, End: iv.Start - 1})
+		}
+		if iv.End >= hi {
+			return res
+		}
+		next = iv.End + 1
+	}
+	res.spans = append(res.spans, Interval{Start: next, End: hi})
+	return res
+}
+
+// Covered returns the total number of values in the set. A set spanning
+// most of int64 holds more values than an int64 can count.
+func (s *IntervalSet) Covered() Accumulator {
+	var acc Accumulator
+	for _, iv := range s.spans {
+		acc.Add(iv.End)
+		acc.Sub(iv.Start)
+		acc.Add(1)
+	}
+	return acc
+}
+
+// Count returns the number of disjoint spans
+func (s *IntervalSet) Count() int {
+	return len(s.spans)
+}
+
+// Spans iterates over the disjoint spans in ascending order
+func (s *IntervalSet) Spans() iter.Seq[Interval] {
+	return func(yield func(Interval) bool) {
+		for _, iv := range s.spans {
+			if !yield(iv) {
+				return
+			}
+		}
+	}
+}
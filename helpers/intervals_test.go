@@ -0,0 +1,79 @@
+package helpers
+
+import (
+	"math"
+	"slices"
+	"testing"
+)
+
+func spans(s *IntervalSet) []Interval {
+	return slices.Collect(s.Spans())
+}
+
+func TestIntervalSetAddRemove(t *testing.T) {
+	s := NewIntervalSet(Interval{3, 5}, Interval{10, 14}, Interval{16, 20}, Interval{12, 18})
+	if got := s.Covered().String(); got != "14" {
+		t.Errorf("got %s, want 14", got)
+	}
+
+	s.Add(6, 8)
+	s.Add(30, 31)
+	want := []Interval{{3, 8}, {10, 20}, {30, 31}}
+	if got := spans(s); !slices.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	s.Remove(12, 13)
+	s.Remove(25, 30)
+	want = []Interval{{3, 8}, {10, 11}, {14, 20}, {31, 31}}
+	if got := spans(s); !slices.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	for x, in := range map[int64]bool{2: false, 3: true, 9: false, 11: true, 12: false, 31: true, 32: false} {
+		if s.Contains(x) != in {
+			t.Errorf("Contains(%d): got %t, want %t", x, !in, in)
+		}
+	}
+}
+
+func TestIntervalSetOps(t *testing.T) {
+	a := NewIntervalSet(Interval{0, 10}, Interval{20, 30})
+	b := NewIntervalSet(Interval{5, 25})
+
+	want := []Interval{{0, 30}}
+	if got := spans(a.Union(b)); !slices.Equal(got, want) {
+		t.Errorf("Union: got %v, want %v", got, want)
+	}
+
+	want = []Interval{{5, 10}, {20, 25}}
+	if got := spans(a.Intersect(b)); !slices.Equal(got, want) {
+		t.Errorf("Intersect: got %v, want %v", got, want)
+	}
+
+	want = []Interval{{-5, -1}, {11, 19}, {31, 40}}
+	if got := spans(a.Complement(-5, 40)); !slices.Equal(got, want) {
+		t.Errorf("Complement: got %v, want %v", got, want)
+	}
+}
+
+func TestIntervalSetLimits(t *testing.T) {
+	s := NewIntervalSet()
+	s.Add(math.MaxInt64-1, math.MaxInt64)
+	s.Add(math.MinInt64, math.MinInt64+1)
+	s.Add(math.MaxInt64-3, math.MaxInt64-2)
+
+	want := []Interval{{math.MinInt64, math.MinInt64 + 1}, {math.MaxInt64 - 3, math.MaxInt64}}
+	if got := spans(s); !slices.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if got := s.Complement(math.MaxInt64-5, math.MaxInt64).Covered().String(); got != "2" {
+		t.Errorf("got %s, want 2", got)
+	}
+
+	// Every int64 is one more than an int64 can hold
+	s.Add(math.MinInt64, math.MaxInt64)
+	if got := s.Covered().String(); got != "18446744073709551616" {
+		t.Errorf("got %s, want 2^64", got)
+	}
+}
@@ -0,0 +1,197 @@
+package linalg
+
+import (
+	"math"
+	"strconv"
+
+	"aoc-2025/helpers"
+)
+
+// IntMatrix is the int64 fast path. Every operation checks for overflow and
+// returns ErrOverflow rather than a wrong answer.
+type IntMatrix [][]int64
+
+func (m IntMatrix) Clone() IntMatrix {
+	c := make(IntMatrix, len(m))
+	for i, row := range m {
+		c[i] = append([]int64(nil), row...)
+	}
+	return c
+}
+
+func (m IntMatrix) Rat() *Matrix {
+	return FromInts(m)
+}
+
+func gcd(a, b int64) int64 {
+	if a < 0 {
+		a = -a
+	}
+	if b < 0 {
+		b = -b
+	}
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
+
+// Reduce is a fraction free version of Matrix.Reduce. Rows are combined with
+// integer multiples and then divided by the gcd of their entries, so a
+// reduced row reads p*x[pivot] + sum(c*x[free]) = rhs with p > 0 rather than
+// having a leading 1. It works on a copy and returns it with the pivots.
+func (m IntMatrix) Reduce(cols int) (IntMatrix, []int, error) {
+	r := m.Clone()
+	var pivots []int
+	row := 0
+	for c := 0; c < cols && row < len(r); c++ {
+		found := -1
+		for i := row; i < len(r); i++ {
+			if r[i][c] != 0 {
+				found = i
+				break
+			}
+		}
+		if found == -1 {
+			continue
+		}
+		r[row], r[found] = r[found], r[row]
+		if err := r.normalise(row, c); err != nil {
+			return nil, nil, err
+		}
+
+		p := r[row][c]
+		for i := range r {
+			if i == row || r[i][c] == 0 {
+				continue
+			}
+			factor := r[i][c]
+			for j := range r[i] {
+				a, err := helpers.CheckedMul(r[i][j], p)
+				if err != nil {
+					return nil, nil, err
+				}
+				b, err := helpers.CheckedMul(r[row][j], factor)
+				if err != nil {
+					return nil, nil, err
+				}
+				if r[i][j], err = helpers.CheckedSub(a, b); err != nil {
+					return nil, nil, err
+				}
+			}
+			if err := r.normalise(i, -1); err != nil {
+				return nil, nil, err
+			}
+		}
+
+		pivots = append(pivots, c)
+		row++
+	}
+	return r, pivots, nil
+}
+
+// normalise divides row i by the gcd of its entries and, if c >= 0, flips
+// its sign so that column c is positive.
+func (m IntMatrix) normalise(i, c int) error {
+	g := int64(0)
+	for _, v := range m[i] {
+		g = gcd(g, v)
+	}
+	if c >= 0 && m[i][c] < 0 {
+		if g == 0 || m[i][c] == math.MinInt64 {
+			return ErrOverflow
+		}
+		g = -g
+	}
+	if g == 0 || g == 1 {
+		return nil
+	}
+	for j := range m[i] {
+		m[i][j] /= g
+	}
+	return nil
+}
+
+// Det computes the determinant with the Bareiss algorithm, which keeps every
+// intermediate value an exact integer.
+func (m IntMatrix) Det() (int64, error) {
+	n := len(m)
+	for _, row := range m {
+		if len(row) != n {
+			return 0, ErrNotSquare
+		}
+	}
+	if n == 0 {
+		return 1, nil
+	}
+
+	a := m.Clone()
+	sign := int64(1)
+	prev := int64(1)
+	for k := 0; k < n-1; k++ {
+		if a[k][k] == 0 {
+			swap := -1
+			for i := k + 1; i < n; i++ {
+				if a[i][k] != 0 {
+					swap = i
+					break
+				}
+			}
+			if swap == -1 {
+				return 0, nil
+			}
+			a[k], a[swap] = a[swap], a[k]
+			sign = -sign
+		}
+		for i := k + 1; i < n; i++ {
+			for j := k + 1; j < n; j++ {
+				x, err := helpers.CheckedMul(a[i][j], a[k][k])
+				if err != nil {
+					return 0, err
+				}
+				y, err := helpers.CheckedMul(a[i][k], a[k][j])
+				if err != nil {
+					return 0, err
+				}
+				if x, err = helpers.CheckedSub(x, y); err != nil {
+					return 0, err
+				}
+				// Bareiss guarantees this division is exact
+				a[i][j] = x / prev
+			}
+		}
+		prev = a[k][k]
+	}
+	return helpers.CheckedMul(sign, a[n-1][n-1])
+}
+
+// IntRREF reduces the first cols columns of m like IntMatrix.Reduce, but if
+// the int64 fast path overflows it redoes the work with rationals and scales
+// the result back to integers.
+func IntRREF(m IntMatrix, cols int) (IntMatrix, []int, error) {
+	r, pivots, err := m.Reduce(cols)
+	if err == nil {
+		return r, pivots, nil
+	}
+	rat := m.Rat()
+	pivots = rat.Reduce(cols, nil)
+	r, err = rat.IntRows()
+	return r, pivots, err
+}
+
+func (m IntMatrix) String() string {
+	cols := 0
+	if len(m) > 0 {
+		cols = len(m[0])
+	}
+	cells := make([][]string, len(m))
+	widths := make([]int, cols)
+	for i, row := range m {
+		cells[i] = make([]string, len(row))
+		for j, v := range row {
+			cells[i][j] = strconv.FormatInt(v, 10)
+			widths[j] = max(widths[j], len(cells[i][j]))
+		}
+	}
+	return formatCells(cells, widths)
+}
@@ -0,0 +1,325 @@
+// Package linalg does exact linear algebra over the rationals. Matrix uses
+// math/big.Rat throughout, and IntMatrix is an int64 fast path that reports
+// ErrOverflow instead of wrapping so callers can fall back to Matrix.
+package linalg
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"strings"
+
+	"aoc-2025/helpers"
+)
+
+var (
+	ErrNoSolution = errors.New("linalg: system has no solution")
+	ErrNotSquare  = errors.New("linalg: matrix is not square")
+	// ErrOverflow is helpers.ErrOverflow, so either can be checked for
+	ErrOverflow = helpers.ErrOverflow
+)
+
+// Matrix is a dense rows x cols matrix of rationals
+type Matrix struct {
+	rows int
+	cols int
+	data [][]*big.Rat
+}
+
+// New returns a zero matrix
+func New(rows, cols int) *Matrix {
+	m := &Matrix{rows: rows, cols: cols, data: make([][]*big.Rat, rows)}
+	for i := range m.data {
+		m.data[i] = make([]*big.Rat, cols)
+		for j := range m.data[i] {
+			m.data[i][j] = new(big.Rat)
+		}
+	}
+	return m
+}
+
+// FromInts builds a matrix from rows of int64. All rows must have the same
+// length.
+func FromInts(rows [][]int64) *Matrix {
+	cols := 0
+	if len(rows) > 0 {
+		cols = len(rows[0])
+	}
+	m := New(len(rows), cols)
+	for i, row := range rows {
+		for j, v := range row {
+			m.data[i][j].SetInt64(v)
+		}
+	}
+	return m
+}
+
+func (m *Matrix) Rows() int { return m.rows }
+func (m *Matrix) Cols() int { return m.cols }
+
+// At returns the entry at row i, column j. The result is shared with the
+// matrix, so copy it before changing it.
+func (m *Matrix) At(i, j int) *big.Rat {
+	return m.data[i][j]
+}
+
+func (m *Matrix) Set(i, j int, v *big.Rat) {
+	m.data[i][j].Set(v)
+}
+
+func (m *Matrix) SetInt(i, j int, v int64) {
+	m.data[i][j].SetInt64(v)
+}
+
+func (m *Matrix) Clone() *Matrix {
+	c := New(m.rows, m.cols)
+	for i := range m.data {
+		for j := range m.data[i] {
+			c.data[i][j].Set(m.data[i][j])
+		}
+	}
+	return c
+}
+
+// Reduce brings the first cols columns to reduced row echelon form in place
+// and returns the pivot column of each leading row. Columns after cols, such
+// as the right hand side of an augmented matrix, are carried along but never
+// picked as pivots. If trace is not nil every step is written to it.
+func (m *Matrix) Reduce(cols int, trace io.Writer) []int {
+	var pivots []int
+	r := 0
+	for c := 0; c < cols && r < m.rows; c++ {
+		found := -1
+		for i := r; i < m.rows; i++ {
+			if m.data[i][c].Sign() != 0 {
+				found = i
+				break
+			}
+		}
+		if found == -1 {
+			continue
+		}
+		if found != r {
+			m.data[r], m.data[found] = m.data[found], m.data[r]
+			tracef(trace, "swap R%d and R%d\n", r+1, found+1)
+		}
+
+		if m.data[r][c].Cmp(big.NewRat(1, 1)) != 0 {
+			inv := new(big.Rat).Inv(m.data[r][c])
+			for _, v := range m.data[r] {
+				v.Mul(v, inv)
+			}
+			tracef(trace, "R%d *= %s\n", r+1, inv.RatString())
+		}
+
+		tmp := new(big.Rat)
+		for i := range m.data {
+			if i == r || m.data[i][c].Sign() == 0 {
+				continue
+			}
+			factor := new(big.Rat).Set(m.data[i][c])
+			for j, v := range m.data[i] {
+				v.Sub(v, tmp.Mul(factor, m.data[r][j]))
+			}
+			tracef(trace, "R%d -= %s * R%d\n", i+1, factor.RatString(), r+1)
+		}
+		if trace != nil {
+			fmt.Fprintln(trace, m)
+		}
+
+		pivots = append(pivots, c)
+		r++
+	}
+	return pivots
+}
+
+func tracef(w io.Writer, format string, args ...any) {
+	if w != nil {
+		fmt.Fprintf(w, format, args...)
+	}
+}
+
+// RREF returns a reduced copy of m and its pivot columns
+func (m *Matrix) RREF() (*Matrix, []int) {
+	r := m.Clone()
+	return r, r.Reduce(r.cols, nil)
+}
+
+func (m *Matrix) Rank() int {
+	_, pivots := m.RREF()
+	return len(pivots)
+}
+
+// Solve returns one solution of m·x = b, with every free variable set to
+// zero, or ErrNoSolution.
+func (m *Matrix) Solve(b []*big.Rat) ([]*big.Rat, error) {
+	if len(b) != m.rows {
+		return nil, fmt.Errorf("linalg: got %d values for %d rows", len(b), m.rows)
+	}
+	aug := New(m.rows, m.cols+1)
+	for i := range m.data {
+		for j, v := range m.data[i] {
+			aug.data[i][j].Set(v)
+		}
+		aug.data[i][m.cols].Set(b[i])
+	}
+
+	pivots := aug.Reduce(m.cols, nil)
+	for _, row := range aug.data[len(pivots):] {
+		if row[m.cols].Sign() != 0 {
+			return nil, ErrNoSolution
+		}
+	}
+
+	x := make([]*big.Rat, m.cols)
+	for j := range x {
+		x[j] = new(big.Rat)
+	}
+	for k, c := range pivots {
+		x[c].Set(aug.data[k][m.cols])
+	}
+	return x, nil
+}
+
+// Nullspace returns a basis of the solutions of m·x = 0, with one vector per
+// free column.
+func (m *Matrix) Nullspace() [][]*big.Rat {
+	r, pivots := m.RREF()
+	isPivot := make([]bool, m.cols)
+	for _, c := range pivots {
+		isPivot[c] = true
+	}
+
+	var basis [][]*big.Rat
+	for f := range m.cols {
+		if isPivot[f] {
+			continue
+		}
+		v := make([]*big.Rat, m.cols)
+		for j := range v {
+			v[j] = new(big.Rat)
+		}
+		v[f].SetInt64(1)
+		for k, c := range pivots {
+			v[c].Neg(r.data[k][f])
+		}
+		basis = append(basis, v)
+	}
+	return basis
+}
+
+// Det returns the determinant. Integer matrices go through the int64 fast
+// path first and only fall back to rationals on overflow.
+func (m *Matrix) Det() (*big.Rat, error) {
+	if m.rows != m.cols {
+		return nil, ErrNotSquare
+	}
+	if im, ok := m.ints(); ok {
+		if d, err := im.Det(); err == nil {
+			return new(big.Rat).SetInt64(d), nil
+		}
+	}
+
+	r := m.Clone()
+	det := big.NewRat(1, 1)
+	for c := 0; c < r.cols; c++ {
+		found := -1
+		for i := c; i < r.rows; i++ {
+			if r.data[i][c].Sign() != 0 {
+				found = i
+				break
+			}
+		}
+		if found == -1 {
+			return new(big.Rat), nil
+		}
+		if found != c {
+			r.data[c], r.data[found] = r.data[found], r.data[c]
+			det.Neg(det)
+		}
+		det.Mul(det, r.data[c][c])
+		tmp := new(big.Rat)
+		for i := c + 1; i < r.rows; i++ {
+			if r.data[i][c].Sign() == 0 {
+				continue
+			}
+			factor := new(big.Rat).Quo(r.data[i][c], r.data[c][c])
+			for j := c; j < r.cols; j++ {
+				r.data[i][j].Sub(r.data[i][j], tmp.Mul(factor, r.data[c][j]))
+			}
+		}
+	}
+	return det, nil
+}
+
+// ints converts m to an IntMatrix if every entry is an int64
+func (m *Matrix) ints() (IntMatrix, bool) {
+	im := make(IntMatrix, m.rows)
+	for i, row := range m.data {
+		im[i] = make([]int64, m.cols)
+		for j, v := range row {
+			if !v.IsInt() || !v.Num().IsInt64() {
+				return nil, false
+			}
+			im[i][j] = v.Num().Int64()
+		}
+	}
+	return im, true
+}
+
+// IntRows scales every row by the lcm of its denominators so that all
+// entries are integers, which keeps the same solutions.
+func (m *Matrix) IntRows() (IntMatrix, error) {
+	im := make(IntMatrix, m.rows)
+	for i, row := range m.data {
+		lcm := big.NewInt(1)
+		for _, v := range row {
+			d := v.Denom()
+			gcd := new(big.Int).GCD(nil, nil, lcm, d)
+			lcm.Mul(lcm, new(big.Int).Quo(d, gcd))
+		}
+		im[i] = make([]int64, m.cols)
+		for j, v := range row {
+			n := new(big.Int).Mul(v.Num(), new(big.Int).Quo(lcm, v.Denom()))
+			if !n.IsInt64() {
+				return nil, ErrOverflow
+			}
+			im[i][j] = n.Int64()
+		}
+	}
+	return im, nil
+}
+
+// String prints the matrix with right aligned columns
+func (m *Matrix) String() string {
+	cells := make([][]string, m.rows)
+	widths := make([]int, m.cols)
+	for i, row := range m.data {
+		cells[i] = make([]string, m.cols)
+		for j, v := range row {
+			cells[i][j] = v.RatString()
+			widths[j] = max(widths[j], len(cells[i][j]))
+		}
+	}
+	return formatCells(cells, widths)
+}
+
+func formatCells(cells [][]string, widths []int) string {
+	var sb strings.Builder
+	for i, row := range cells {
+		if i > 0 {
+			sb.WriteByte('\n')
+		}
+		sb.WriteString("[")
+		for j, cell := range row {
+			if j > 0 {
+				sb.WriteByte(' ')
+			}
+			sb.WriteString(strings.Repeat(" ", widths[j]-len(cell)))
+			sb.WriteString(cell)
+		}
+		sb.WriteString("]")
+	}
+	return sb.String()
+}
@@ -0,0 +1,131 @@
+package linalg
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+func rats(vals ...int64) []*big.Rat {
+	res := make([]*big.Rat, len(vals))
+	for i, v := range vals {
+		res[i] = big.NewRat(v, 1)
+	}
+	return res
+}
+
+func TestSolve(t *testing.T) {
+	m := FromInts([][]int64{
+		{2, 1, -1},
+		{-3, -1, 2},
+		{-2, 1, 2},
+	})
+	x, err := m.Solve(rats(8, -11, -3))
+	if err != nil {
+		t.Fatalf("Solve failed: %v", err)
+	}
+	want := []string{"2", "3", "-1"}
+	for i, v := range x {
+		if v.RatString() != want[i] {
+			t.Errorf("x[%d]: got %s, want %s", i, v.RatString(), want[i])
+		}
+	}
+
+	singular := FromInts([][]int64{{1, 1}, {1, 1}})
+	if _, err := singular.Solve(rats(1, 2)); !errors.Is(err, ErrNoSolution) {
+		t.Errorf("got %v, want ErrNoSolution", err)
+	}
+}
+
+func TestRankAndNullspace(t *testing.T) {
+	m := FromInts([][]int64{
+		{1, 2, 3},
+		{2, 4, 6},
+		{1, 0, 1},
+	})
+	if got := m.Rank(); got != 2 {
+		t.Errorf("got rank %d, want 2", got)
+	}
+
+	basis := m.Nullspace()
+	if len(basis) != 1 {
+		t.Fatalf("got %d basis vectors, want 1", len(basis))
+	}
+	for i := range m.Rows() {
+		acc := new(big.Rat)
+		for j, v := range basis[0] {
+			acc.Add(acc, new(big.Rat).Mul(m.At(i, j), v))
+		}
+		if acc.Sign() != 0 {
+			t.Errorf("row %d: m·v = %s, want 0", i, acc.RatString())
+		}
+	}
+}
+
+func TestDet(t *testing.T) {
+	m := FromInts([][]int64{
+		{0, 2, 1},
+		{3, 1, 4},
+		{5, 9, 2},
+	})
+	d, err := m.Det()
+	if err != nil || d.RatString() != "50" {
+		t.Errorf("got %v, %v, want 50", d, err)
+	}
+
+	// Overflows the int64 fast path, so it has to fall back to rationals
+	huge := FromInts([][]int64{
+		{math.MaxInt64, 0},
+		{0, math.MaxInt64},
+	})
+	if _, err := IntMatrix([][]int64{{math.MaxInt64, 0}, {0, math.MaxInt64}}).Det(); !errors.Is(err, ErrOverflow) {
+		t.Errorf("got %v, want ErrOverflow", err)
+	}
+	d, err = huge.Det()
+	if err != nil || d.RatString() != "85070591730234615847396907784232501249" {
+		t.Errorf("got %v, %v", d, err)
+	}
+}
+
+func TestDetRational(t *testing.T) {
+	m := New(2, 2)
+	m.Set(0, 0, big.NewRat(1, 2))
+	m.Set(0, 1, big.NewRat(1, 3))
+	m.Set(1, 0, big.NewRat(1, 4))
+	m.Set(1, 1, big.NewRat(1, 5))
+	d, err := m.Det()
+	if err != nil || d.RatString() != "1/60" {
+		t.Errorf("got %v, %v, want 1/60", d, err)
+	}
+}
+
+func TestIntRREF(t *testing.T) {
+	// Augmented system with a fractional solution
+	m := IntMatrix{
+		{2, 1, 1},
+		{1, 3, 2},
+	}
+	r, pivots, err := IntRREF(m, 2)
+	if err != nil {
+		t.Fatalf("IntRREF failed: %v", err)
+	}
+	if len(pivots) != 2 {
+		t.Fatalf("got %d pivots, want 2", len(pivots))
+	}
+	// x = 1/5, y = 3/5
+	if r[0][0] != 5 || r[0][2] != 1 || r[1][1] != 5 || r[1][2] != 3 {
+		t.Errorf("got\n%s", r)
+	}
+}
+
+func TestTrace(t *testing.T) {
+	m := FromInts([][]int64{{0, 2}, {1, 1}})
+	var sb strings.Builder
+	m.Reduce(2, &sb)
+	out := sb.String()
+	if !strings.Contains(out, "swap R1 and R2") || !strings.Contains(out, "R2 *= 1/2") {
+		t.Errorf("unexpected trace:\n%s", out)
+	}
+}
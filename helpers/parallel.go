@@ -0,0 +1,107 @@
+package helpers
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+type parallelConfig struct {
+	workers   int
+	chunkSize int
+}
+
+type ParallelOption func(*parallelConfig)
+
+// WithWorkers sets the number of goroutines, which defaults to GOMAXPROCS
+func WithWorkers(n int) ParallelOption {
+	return func(c *parallelConfig) {
+		c.workers = n
+	}
+}
+
+// WithChunkSize sets how many items a worker takes at a time. Larger chunks
+// cut the overhead for cheap items. By default each worker gets about four
+// chunks.
+func WithChunkSize(n int) ParallelOption {
+	return func(c *parallelConfig) {
+		c.chunkSize = n
+	}
+}
+
+// ParallelMap calls fn on every item using a pool of workers and returns the
+// results in the same order as items. The first error cancels the context
+// passed to fn, stops handing out work and is returned.
+func ParallelMap[T, R any](ctx context.Context, items []T, fn func(ctx context.Context, i int, item T) (R, error), opts ...ParallelOption) ([]R, error) {
+	cfg := parallelConfig{workers: runtime.GOMAXPROCS(0)}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	cfg.workers = max(1, min(cfg.workers, len(items)))
+	if cfg.chunkSize <= 0 {
+		cfg.chunkSize = max(1, len(items)/(cfg.workers*4))
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make([]R, len(items))
+	var next atomic.Int64
+	var firstErr error
+	var once sync.Once
+	var wg sync.WaitGroup
+
+	fail := func(err error) {
+		once.Do(func() {
+			firstErr = err
+			cancel()
+		})
+	}
+
+	for w := 0; w < cfg.workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				start := int(next.Add(int64(cfg.chunkSize))) - cfg.chunkSize
+				if start >= len(items) {
+					return
+				}
+				end := min(start+cfg.chunkSize, len(items))
+				for i := start; i < end; i++ {
+					if err := ctx.Err(); err != nil {
+						fail(err)
+						return
+					}
+					res, err := fn(ctx, i, items[i])
+					if err != nil {
+						fail(err)
+						return
+					}
+					results[i] = res
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	return results, nil
+}
+
+// ParallelReduce maps every item in parallel like ParallelMap and then folds
+// the results in order, so the reduction does not need to be commutative.
+func ParallelReduce[T, R, A any](ctx context.Context, items []T, fn func(ctx context.Context, i int, item T) (R, error), init A, reduce func(acc A, res R) A, opts ...ParallelOption) (A, error) {
+	results, err := ParallelMap(ctx, items, fn, opts...)
+	if err != nil {
+		return init, err
+	}
+	acc := init
+	for _, res := range results {
+		acc = reduce(acc, res)
+	}
+	return acc, nil
+}
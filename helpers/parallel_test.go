@@ -0,0 +1,59 @@
+package helpers
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestParallelMap(t *testing.T) {
+	items := make([]int, 1000)
+	for i := range items {
+		items[i] = i
+	}
+	square := func(_ context.Context, _ int, x int) (int, error) {
+		return x * x, nil
+	}
+
+	for _, opts := range [][]ParallelOption{nil, {WithWorkers(1)}, {WithWorkers(7), WithChunkSize(13)}} {
+		res, err := ParallelMap(context.Background(), items, square, opts...)
+		if err != nil {
+			t.Fatalf("ParallelMap failed: %v", err)
+		}
+		for i, r := range res {
+			if r != i*i {
+				t.Fatalf("res[%d]: got %d, want %d", i, r, i*i)
+			}
+		}
+	}
+
+	sum, err := ParallelReduce(context.Background(), items, square, 0, func(acc, r int) int { return acc + r })
+	if err != nil || sum != 332833500 {
+		t.Errorf("got %d, %v, want 332833500", sum, err)
+	}
+}
+
+func TestParallelMapError(t *testing.T) {
+	items := make([]int, 1000)
+	boom := errors.New("boom")
+	_, err := ParallelMap(context.Background(), items, func(ctx context.Context, i int, _ int) (int, error) {
+		if i == 500 {
+			return 0, boom
+		}
+		return i, nil
+	}, WithWorkers(4))
+	if !errors.Is(err, boom) {
+		t.Errorf("got %v, want boom", err)
+	}
+}
+
+func TestParallelMapCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := ParallelMap(ctx, []int{1, 2, 3}, func(_ context.Context, _ int, x int) (int, error) {
+		return x, nil
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("got %v, want context.Canceled", err)
+	}
+}
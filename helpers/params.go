@@ -0,0 +1,143 @@
+package helpers
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Params holds name=value options passed to a solution from the command line
+type Params map[string]string
+
+// Set parses "name=value" and stores it, so Params can be used with flag.Func
+func (p Params) Set(s string) error {
+	name, value, ok := strings.Cut(s, "=")
+	if !ok || name == "" {
+		return fmt.Errorf("expected name=value, got %q", s)
+	}
+	p[name] = value
+	return nil
+}
+
+// ParamKind is the type of value a parameter takes
+type ParamKind int
+
+const (
+	ParamInt ParamKind = iota + 1
+	ParamString
+)
+
+func (k ParamKind) String() string {
+	switch k {
+	case ParamInt:
+		return "int"
+	case ParamString:
+		return "string"
+	default:
+		return fmt.Sprintf("ParamKind(%d)", int(k))
+	}
+}
+
+// Param declares a parameter a solution accepts. Defaults are the values
+// for the real input; the examples often need something smaller. Default
+// must be an int for ParamInt and a string for ParamString, and values given
+// for the parameter are parsed to that type.
+type Param struct {
+	Name    string
+	Kind    ParamKind
+	Default any
+	Usage   string
+}
+
+// check reports a declaration whose default doesn't match its kind
+func (p Param) check() error {
+	ok := false
+	switch p.Kind {
+	case ParamInt:
+		_, ok = p.Default.(int)
+	case ParamString:
+		_, ok = p.Default.(string)
+	default:
+		return fmt.Errorf("parameter %s: unsupported kind %v", p.Name, p.Kind)
+	}
+	if !ok {
+		return fmt.Errorf("parameter %s: default %v is a %T, not a %v", p.Name, p.Default, p.Default, p.Kind)
+	}
+	return nil
+}
+
+// Values are resolved parameters, read with the getter matching the
+// parameter's kind. Resolve has already checked every value against its
+// kind, so a getter only returns the zero value for a name that wasn't
+// declared with that kind.
+type Values map[string]any
+
+func (v Values) Int(name string) int {
+	n, _ := v[name].(int)
+	return n
+}
+
+func (v Values) String(name string) string {
+	s, _ := v[name].(string)
+	return s
+}
+
+// List splits a string parameter on commas, so "a,b" is [a b] and "" is empty
+func (v Values) List(name string) []string {
+	s := v.String(name)
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, ",")
+}
+
+// Resolve checks params against the declared parameters and returns the value
+// of every declared parameter, using the default for any that are not set.
+// Setting a parameter that isn't declared is an error, so typos don't go
+// unnoticed, and so is a declaration whose default doesn't match its kind.
+func Resolve(decls []Param, params Params) (Values, error) {
+	values := make(Values, len(decls))
+	kinds := make(map[string]ParamKind, len(decls))
+	for _, d := range decls {
+		if err := d.check(); err != nil {
+			return nil, err
+		}
+		values[d.Name] = d.Default
+		kinds[d.Name] = d.Kind
+	}
+
+	names := make([]string, 0, len(params))
+	for name := range params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		kind, known := kinds[name]
+		if !known {
+			return nil, fmt.Errorf("unknown parameter %q (%s)", name, describe(decls))
+		}
+		if kind == ParamString {
+			values[name] = params[name]
+			continue
+		}
+		n, err := strconv.Atoi(params[name])
+		if err != nil {
+			return nil, fmt.Errorf("parameter %s: %w", name, err)
+		}
+		values[name] = n
+	}
+	return values, nil
+}
+
+func describe(decls []Param) string {
+	if len(decls) == 0 {
+		return "this solution takes no parameters"
+	}
+	parts := make([]string, len(decls))
+	for i, d := range decls {
+		parts[i] = fmt.Sprintf("%s=%v: %s", d.Name, d.Default, d.Usage)
+	}
+	return "accepts " + strings.Join(parts, ", ")
+}
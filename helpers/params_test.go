@@ -0,0 +1,75 @@
+package helpers
+
+import "testing"
+
+func TestResolve(t *testing.T) {
+	decls := []Param{
+		{Name: "steps", Kind: ParamInt, Default: 1000},
+		{Name: "top", Kind: ParamInt, Default: 3},
+	}
+
+	values, err := Resolve(decls, Params{"steps": "10"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if values.Int("steps") != 10 || values.Int("top") != 3 {
+		t.Errorf("got %v, want steps=10 top=3", values)
+	}
+
+	if _, err := Resolve(decls, Params{"step": "10"}); err == nil {
+		t.Error("expected an error for an undeclared parameter")
+	}
+	if _, err := Resolve(decls, Params{"top": "three"}); err == nil {
+		t.Error("expected an error for a value that isn't a number")
+	}
+	if _, err := Resolve(nil, Params{"k": "1"}); err == nil {
+		t.Error("expected an error when the solution takes no parameters")
+	}
+}
+
+func TestResolveStrings(t *testing.T) {
+	decls := []Param{
+		{Name: "start", Kind: ParamString, Default: "svr"},
+		{Name: "via", Kind: ParamString, Default: ""},
+	}
+
+	values, err := Resolve(decls, Params{"via": "dac,fft"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if values.String("start") != "svr" {
+		t.Errorf("got start %q, want the default", values.String("start"))
+	}
+	if via := values.List("via"); len(via) != 2 || via[0] != "dac" || via[1] != "fft" {
+		t.Errorf("got via %q, want [dac fft]", via)
+	}
+
+	values, _ = Resolve(decls, nil)
+	if via := values.List("via"); via != nil {
+		t.Errorf("got via %q, want none", via)
+	}
+}
+
+func TestResolveBadDeclaration(t *testing.T) {
+	tests := []Param{
+		{Name: "ratio", Kind: ParamInt, Default: 0.5},
+		{Name: "k", Kind: ParamString, Default: 2},
+		{Name: "k", Default: 2},
+		{Name: "k", Kind: ParamInt, Default: int64(2)},
+	}
+	for _, d := range tests {
+		if _, err := Resolve([]Param{d}, nil); err == nil {
+			t.Errorf("%+v: expected an error for a default that doesn't match the kind", d)
+		}
+	}
+}
+
+func TestParamsSet(t *testing.T) {
+	p := Params{}
+	if err := p.Set("top=3"); err != nil || p["top"] != "3" {
+		t.Errorf("got %v, %v", p, err)
+	}
+	if err := p.Set("top"); err == nil {
+		t.Error("expected an error without '='")
+	}
+}
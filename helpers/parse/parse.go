@@ -0,0 +1,217 @@
+// Package parse has small, allocation light parsers for common puzzle input
+// shapes. Everything validates its input and returns an error instead of
+// silently skipping bad data.
+package parse
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Integer is any built in integer type
+type Integer interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// Ints extracts every integer in s, treating a '-' directly before a digit
+// as a sign unless it follows another digit (so "3-5" is 3 and 5).
+func Ints(s string) ([]int64, error) {
+	var res []int64
+	for i := 0; i < len(s); {
+		if !isDigit(s[i]) {
+			i++
+			continue
+		}
+		start := i
+		if start > 0 && s[start-1] == '-' && (start < 2 || !isDigit(s[start-2])) {
+			start--
+		}
+		for i < len(s) && isDigit(s[i]) {
+			i++
+		}
+		n, err := strconv.ParseInt(s[start:i], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("parse: invalid integer %q: %w", s[start:i], err)
+		}
+		res = append(res, n)
+	}
+	return res, nil
+}
+
+// Blocks splits lines into groups separated by one or more blank lines
+func Blocks(lines []string) [][]string {
+	var blocks [][]string
+	start := -1
+	for i, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			if start != -1 {
+				blocks = append(blocks, lines[start:i])
+				start = -1
+			}
+			continue
+		}
+		if start == -1 {
+			start = i
+		}
+	}
+	if start != -1 {
+		blocks = append(blocks, lines[start:])
+	}
+	return blocks
+}
+
+// CSV parses a sep separated list of integers such as "1,2,3". Whitespace
+// around each field is ignored and an empty string gives an empty slice.
+func CSV[T Integer](s string, sep string) ([]T, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil, nil
+	}
+	fields := strings.Split(s, sep)
+	res := make([]T, len(fields))
+	for i, field := range fields {
+		n, err := Int[T](strings.TrimSpace(field))
+		if err != nil {
+			return nil, err
+		}
+		res[i] = n
+	}
+	return res, nil
+}
+
+// Int parses a single integer into T, failing if it does not fit
+func Int[T Integer](s string) (T, error) {
+	var zero T
+	if strings.HasPrefix(s, "-") {
+		n, err := strconv.ParseInt(s, 10, 64)
+		if err != nil || int64(T(n)) != n || T(n) > 0 {
+			return zero, fmt.Errorf("parse: invalid integer %q", s)
+		}
+		return T(n), nil
+	}
+	n, err := strconv.ParseUint(s, 10, 64)
+	if err != nil || uint64(T(n)) != n || T(n) < 0 {
+		return zero, fmt.Errorf("parse: invalid integer %q", s)
+	}
+	return T(n), nil
+}
+
+// Range parses "a-b" into its two ends and checks that a <= b
+func Range(s string) (int64, int64, error) {
+	a, b, ok := strings.Cut(strings.TrimSpace(s), "-")
+	if !ok {
+		return 0, 0, fmt.Errorf("parse: invalid range %q", s)
+	}
+	start, err := strconv.ParseInt(a, 10, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("parse: invalid range %q: %w", s, err)
+	}
+	end, err := strconv.ParseInt(b, 10, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("parse: invalid range %q: %w", s, err)
+	}
+	if end < start {
+		return 0, 0, fmt.Errorf("parse: range %q ends before it starts", s)
+	}
+	return start, end, nil
+}
+
+// Column is one fixed width column found by Columns. Cells holds the text of
+// every row in the column, padded with spaces so they all have the same
+// width even when input lines are ragged.
+type Column struct {
+	Start  int
+	End    int // exclusive
+	Marker string
+	Cells  []string
+}
+
+// Columns splits rows into fixed width columns. Each run of non space
+// characters in the marker row starts a column, and the column runs up to
+// the single separator character before the next one. The last column runs
+// to the end of the widest row. It fails if a separator is not blank.
+func Columns(rows []string, marker string) ([]Column, error) {
+	width := len(marker)
+	for _, row := range rows {
+		width = max(width, len(row))
+	}
+
+	var cols []Column
+	for i := 0; i < len(marker); i++ {
+		if marker[i] == ' ' || (i > 0 && marker[i-1] != ' ') {
+			continue
+		}
+		if len(cols) > 0 {
+			cols[len(cols)-1].End = i - 1
+		}
+		cols = append(cols, Column{Start: i})
+	}
+	if len(cols) == 0 {
+		return nil, fmt.Errorf("parse: marker row has no columns")
+	}
+	cols[len(cols)-1].End = width
+
+	for c := range cols {
+		col := &cols[c]
+		col.Marker = strings.TrimRight(cell(marker, col.Start, col.End), " ")
+		if col.End <= col.Start {
+			return nil, fmt.Errorf("parse: column %d at %d has no width", c+1, col.Start)
+		}
+		col.Cells = make([]string, len(rows))
+		for r, row := range rows {
+			if c < len(cols)-1 && col.End < len(row) && row[col.End] != ' ' {
+				return nil, fmt.Errorf("parse: row %d: expected blank separator at column %d, got %q", r+1, col.End+1, row[col.End])
+			}
+			col.Cells[r] = cell(row, col.Start, col.End)
+		}
+	}
+	return cols, nil
+}
+
+// cell returns s[start:end], padding with spaces past the end of s
+func cell(s string, start, end int) string {
+	if end <= len(s) {
+		return s[start:end]
+	}
+	var sb strings.Builder
+	sb.Grow(end - start)
+	if start < len(s) {
+		sb.WriteString(s[start:])
+	}
+	for sb.Len() < end-start {
+		sb.WriteByte(' ')
+	}
+	return sb.String()
+}
+
+// Groups returns the contents of every open...close group in s, such as
+// the "0,2" and "3" in "(0,2) (3)". Nested or unbalanced groups are errors.
+func Groups(s string, open, close byte) ([]string, error) {
+	var groups []string
+	start := -1
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case open:
+			if start != -1 {
+				return nil, fmt.Errorf("parse: nested %c at %d in %q", open, i, s)
+			}
+			start = i + 1
+		case close:
+			if start == -1 {
+				return nil, fmt.Errorf("parse: unexpected %c at %d in %q", close, i, s)
+			}
+			groups = append(groups, s[start:i])
+			start = -1
+		}
+	}
+	if start != -1 {
+		return nil, fmt.Errorf("parse: unclosed %c in %q", open, s)
+	}
+	return groups, nil
+}
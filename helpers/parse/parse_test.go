@@ -0,0 +1,105 @@
+package parse
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestInts(t *testing.T) {
+	got, err := Ints("move -3 from 10-14 to x=-7,y=42")
+	if err != nil {
+		t.Fatalf("Ints failed: %v", err)
+	}
+	want := []int64{-3, 10, 14, -7, 42}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	if _, err := Ints("99999999999999999999"); err == nil {
+		t.Errorf("expected overflow error")
+	}
+}
+
+func TestBlocks(t *testing.T) {
+	got := Blocks([]string{"", "3-5", "10-14", "", "", "1", "5", ""})
+	want := [][]string{{"3-5", "10-14"}, {"1", "5"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestCSV(t *testing.T) {
+	got, err := CSV[int](" 162, 817,812 ", ",")
+	if err != nil {
+		t.Fatalf("CSV failed: %v", err)
+	}
+	if !reflect.DeepEqual(got, []int{162, 817, 812}) {
+		t.Errorf("got %v", got)
+	}
+
+	if _, err := CSV[uint8]("1,256", ","); err == nil {
+		t.Errorf("expected error for value out of range")
+	}
+	if _, err := CSV[uint]("-1", ","); err == nil {
+		t.Errorf("expected error for negative unsigned value")
+	}
+	if _, err := CSV[int]("1,,2", ","); err == nil {
+		t.Errorf("expected error for empty field")
+	}
+}
+
+func TestRange(t *testing.T) {
+	start, end, err := Range("998-1012")
+	if err != nil || start != 998 || end != 1012 {
+		t.Errorf("got %d-%d, %v", start, end, err)
+	}
+	if _, _, err := Range("10-3"); err == nil {
+		t.Errorf("expected error for reversed range")
+	}
+	if _, _, err := Range("10"); err == nil {
+		t.Errorf("expected error for missing dash")
+	}
+}
+
+func TestColumns(t *testing.T) {
+	rows := []string{
+		"123 328  51 64 ",
+		" 45 64  387 23",
+		"  6 98  215 314",
+	}
+	cols, err := Columns(rows, "*   +   *   +  ")
+	if err != nil {
+		t.Fatalf("Columns failed: %v", err)
+	}
+	if len(cols) != 4 {
+		t.Fatalf("got %d columns, want 4", len(cols))
+	}
+	if cols[1].Marker != "+" || cols[1].Start != 4 || cols[1].End != 7 {
+		t.Errorf("got %+v", cols[1])
+	}
+	want := []string{"64 ", "23 ", "314"}
+	if !reflect.DeepEqual(cols[3].Cells, want) {
+		t.Errorf("got %q, want %q", cols[3].Cells, want)
+	}
+
+	if _, err := Columns([]string{"1234"}, "* *"); err == nil {
+		t.Errorf("expected error for non blank separator")
+	}
+}
+
+func TestGroups(t *testing.T) {
+	got, err := Groups("[.##.] (3) (1,3) () {3,5}", '(', ')')
+	if err != nil {
+		t.Fatalf("Groups failed: %v", err)
+	}
+	want := []string{"3", "1,3", ""}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	for _, s := range []string{"(1", "1)", "((1))"} {
+		if _, err := Groups(s, '(', ')'); err == nil {
+			t.Errorf("expected error for %q", s)
+		}
+	}
+}
@@ -25,3 +25,69 @@ func (q *Queue[T]) IsEmpty() bool {
 func (q *Queue[T]) Len() int {
 	return len(q.items)
 }
+
+// Heap is a binary min-heap ordered by less
+type Heap[T any] struct {
+	items []T
+	less  func(a, b T) bool
+}
+
+func NewHeap[T any](less func(a, b T) bool) *Heap[T] {
+	return &Heap[T]{less: less}
+}
+
+func (h *Heap[T]) Push(item T) {
+	h.items = append(h.items, item)
+	i := len(h.items) - 1
+	for i > 0 {
+		parent := (i - 1) / 2
+		if !h.less(h.items[i], h.items[parent]) {
+			break
+		}
+		h.items[i], h.items[parent] = h.items[parent], h.items[i]
+		i = parent
+	}
+}
+
+func (h *Heap[T]) Pop() (T, bool) {
+	if len(h.items) == 0 {
+		var zero T
+		return zero, false
+	}
+	top := h.items[0]
+	last := len(h.items) - 1
+	h.items[0] = h.items[last]
+	h.items = h.items[:last]
+
+	i := 0
+	for {
+		smallest := i
+		for _, child := range []int{2*i + 1, 2*i + 2} {
+			if child < len(h.items) && h.less(h.items[child], h.items[smallest]) {
+				smallest = child
+			}
+		}
+		if smallest == i {
+			break
+		}
+		h.items[i], h.items[smallest] = h.items[smallest], h.items[i]
+		i = smallest
+	}
+	return top, true
+}
+
+func (h *Heap[T]) Peek() (T, bool) {
+	if len(h.items) == 0 {
+		var zero T
+		return zero, false
+	}
+	return h.items[0], true
+}
+
+func (h *Heap[T]) IsEmpty() bool {
+	return len(h.items) == 0
+}
+
+func (h *Heap[T]) Len() int {
+	return len(h.items)
+}
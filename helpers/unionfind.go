@@ -0,0 +1,208 @@
+package helpers
+
+// UnionFind is a disjoint set over the ints 0..n-1 using union by size. The
+// number of components and the size of each one are tracked as we go, so
+// both are O(1) to query.
+type UnionFind struct {
+	parent   []int
+	size     []int
+	count    int
+	rollback bool
+	history  []int // roots that were attached to another root, newest last
+}
+
+func NewUnionFind(n int) *UnionFind {
+	uf := &UnionFind{
+		parent: make([]int, n),
+		size:   make([]int, n),
+		count:  n,
+	}
+	for i := 0; i < n; i++ {
+		uf.parent[i] = i
+		uf.size[i] = 1
+	}
+	return uf
+}
+
+// NewRollbackUnionFind returns a union-find where Rollback can undo unions.
+// Path compression is disabled so every merge can be reverted, which makes
+// Find O(log n) instead of nearly constant.
+func NewRollbackUnionFind(n int) *UnionFind {
+	uf := NewUnionFind(n)
+	uf.rollback = true
+	return uf
+}
+
+// Find returns the root of the component containing x
+func (uf *UnionFind) Find(x int) int {
+	root := x
+	for uf.parent[root] != root {
+		root = uf.parent[root]
+	}
+	if !uf.rollback {
+		for uf.parent[x] != root {
+			next := uf.parent[x]
+			uf.parent[x] = root
+			x = next
+		}
+	}
+	return root
+}
+
+// Union merges the components containing x and y, attaching the smaller one
+// to the larger. It returns false if they were already connected.
+func (uf *UnionFind) Union(x, y int) bool {
+	rootX := uf.Find(x)
+	rootY := uf.Find(y)
+
+	if rootX == rootY {
+		return false
+	}
+
+	if uf.size[rootX] < uf.size[rootY] {
+		rootX, rootY = rootY, rootX
+	}
+	uf.parent[rootY] = rootX
+	uf.size[rootX] += uf.size[rootY]
+	uf.count--
+
+	if uf.rollback {
+		uf.history = append(uf.history, rootY)
+	}
+	return true
+}
+
+// Rollback undoes the most recent successful Union. It returns false if
+// there is nothing to undo or the union-find was not created with rollback.
+func (uf *UnionFind) Rollback() bool {
+	if len(uf.history) == 0 {
+		return false
+	}
+	child := uf.history[len(uf.history)-1]
+	uf.history = uf.history[:len(uf.history)-1]
+
+	root := uf.parent[child]
+	uf.parent[child] = child
+	uf.size[root] -= uf.size[child]
+	uf.count++
+	return true
+}
+
+func (uf *UnionFind) Connected(x, y int) bool {
+	return uf.Find(x) == uf.Find(y)
+}
+
+// Size returns the number of elements in the component containing x
+func (uf *UnionFind) Size(x int) int {
+	return uf.size[uf.Find(x)]
+}
+
+// Count returns the number of components
+func (uf *UnionFind) Count() int {
+	return uf.count
+}
+
+func (uf *UnionFind) Len() int {
+	return len(uf.parent)
+}
+
+// Components returns every component with its members in ascending order.
+// Components are ordered by their smallest member.
+func (uf *UnionFind) Components() [][]int {
+	slot := make(map[int]int, uf.count)
+	comps := make([][]int, 0, uf.count)
+	for i := 0; i < len(uf.parent); i++ {
+		root := uf.Find(i)
+		idx, exists := slot[root]
+		if !exists {
+			idx = len(comps)
+			slot[root] = idx
+			comps = append(comps, make([]int, 0, uf.size[root]))
+		}
+		comps[idx] = append(comps[idx], i)
+	}
+	return comps
+}
+
+// KeyedUnionFind is a UnionFind over arbitrary comparable keys. Keys are
+// added by Add and Union; the queries never add a key they don't know.
+type KeyedUnionFind[K comparable] struct {
+	uf    *UnionFind
+	index map[K]int
+	keys  []K
+}
+
+func NewKeyedUnionFind[K comparable]() *KeyedUnionFind[K] {
+	return &KeyedUnionFind[K]{uf: NewUnionFind(0), index: make(map[K]int)}
+}
+
+func NewRollbackKeyedUnionFind[K comparable]() *KeyedUnionFind[K] {
+	return &KeyedUnionFind[K]{uf: NewRollbackUnionFind(0), index: make(map[K]int)}
+}
+
+// Add inserts k as its own component if it is not already present
+func (k *KeyedUnionFind[K]) Add(key K) int {
+	if id, exists := k.index[key]; exists {
+		return id
+	}
+	id := len(k.keys)
+	k.index[key] = id
+	k.keys = append(k.keys, key)
+	k.uf.parent = append(k.uf.parent, id)
+	k.uf.size = append(k.uf.size, 1)
+	k.uf.count++
+	return id
+}
+
+// Find returns the representative key of the component containing key, and
+// false if key was never added
+func (k *KeyedUnionFind[K]) Find(key K) (K, bool) {
+	id, exists := k.index[key]
+	if !exists {
+		var zero K
+		return zero, false
+	}
+	return k.keys[k.uf.Find(id)], true
+}
+
+func (k *KeyedUnionFind[K]) Union(a, b K) bool {
+	return k.uf.Union(k.Add(a), k.Add(b))
+}
+
+func (k *KeyedUnionFind[K]) Rollback() bool {
+	return k.uf.Rollback()
+}
+
+// Connected reports false if either key was never added
+func (k *KeyedUnionFind[K]) Connected(a, b K) bool {
+	ia, okA := k.index[a]
+	ib, okB := k.index[b]
+	return okA && okB && k.uf.Connected(ia, ib)
+}
+
+// Size returns the size of the component containing key, or 0 if key was
+// never added
+func (k *KeyedUnionFind[K]) Size(key K) int {
+	id, exists := k.index[key]
+	if !exists {
+		return 0
+	}
+	return k.uf.Size(id)
+}
+
+func (k *KeyedUnionFind[K]) Count() int {
+	return k.uf.Count()
+}
+
+// Components returns every component, with members in insertion order
+func (k *KeyedUnionFind[K]) Components() [][]K {
+	ids := k.uf.Components()
+	comps := make([][]K, len(ids))
+	for i, members := range ids {
+		comps[i] = make([]K, len(members))
+		for j, id := range members {
+			comps[i][j] = k.keys[id]
+		}
+	}
+	return comps
+}
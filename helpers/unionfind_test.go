@@ -0,0 +1,91 @@
+package helpers
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestUnionFind(t *testing.T) {
+	uf := NewUnionFind(6)
+	uf.Union(0, 1)
+	uf.Union(2, 3)
+	uf.Union(1, 3)
+
+	if uf.Union(0, 2) {
+		t.Errorf("expected 0 and 2 to already be connected")
+	}
+	if got := uf.Count(); got != 3 {
+		t.Errorf("got %d components, want 3", got)
+	}
+	if got := uf.Size(3); got != 4 {
+		t.Errorf("got size %d, want 4", got)
+	}
+
+	want := [][]int{{0, 1, 2, 3}, {4}, {5}}
+	if got := uf.Components(); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestUnionFindRollback(t *testing.T) {
+	uf := NewRollbackUnionFind(4)
+	uf.Union(0, 1)
+	uf.Union(2, 3)
+	uf.Union(0, 2)
+
+	if !uf.Rollback() {
+		t.Fatalf("expected rollback to succeed")
+	}
+	if uf.Connected(0, 2) {
+		t.Errorf("expected 0 and 2 to be split again")
+	}
+	if got := uf.Count(); got != 2 {
+		t.Errorf("got %d components, want 2", got)
+	}
+	if got := uf.Size(0); got != 2 {
+		t.Errorf("got size %d, want 2", got)
+	}
+
+	uf.Rollback()
+	uf.Rollback()
+	if uf.Rollback() {
+		t.Errorf("expected nothing left to roll back")
+	}
+}
+
+func TestKeyedUnionFind(t *testing.T) {
+	uf := NewKeyedUnionFind[string]()
+	uf.Union("a", "b")
+	uf.Union("c", "b")
+	uf.Add("d")
+
+	if !uf.Connected("a", "c") {
+		t.Errorf("expected a and c to be connected")
+	}
+	if got := uf.Count(); got != 2 {
+		t.Errorf("got %d components, want 2", got)
+	}
+
+	want := [][]string{{"a", "b", "c"}, {"d"}}
+	if got := uf.Components(); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	if root, ok := uf.Find("c"); !ok || root != "a" && root != "b" && root != "c" {
+		t.Errorf("got %q, %v, want a member of {a b c}", root, ok)
+	}
+
+	// Asking about a key that was never added must not add it
+	if _, ok := uf.Find("x"); ok {
+		t.Errorf("expected Find to report x as missing")
+	}
+	if uf.Connected("x", "x") || uf.Connected("a", "x") {
+		t.Errorf("expected x to be connected to nothing")
+	}
+	if got := uf.Size("x"); got != 0 {
+		t.Errorf("got size %d, want 0", got)
+	}
+	if got := uf.Count(); got != 2 {
+		t.Errorf("got %d components after queries, want 2", got)
+	}
+}
@@ -2,8 +2,10 @@
 package main
 
 import (
+	"encoding/json"
 	"flag"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"time"
@@ -19,99 +21,218 @@ import (
 	"aoc-2025/day09"
 	"aoc-2025/day10"
 	"aoc-2025/day11"
+	"aoc-2025/helpers"
 )
 
-type SolutionFunc func(string) (string, error)
+type SolutionFunc func(string, helpers.Params) (helpers.Answer, error)
+
+// textual adapts a solution that returns its answer as a string
+func textual(f func(string, helpers.Params) (string, error)) SolutionFunc {
+	return func(inputFile string, params helpers.Params) (helpers.Answer, error) {
+		res, err := f(inputFile, params)
+		if err != nil {
+			return helpers.Answer{}, err
+		}
+		return helpers.ParseAnswer(res), nil
+	}
+}
+
+// noParams adapts a solution that takes no parameters, rejecting any that are
+// passed so a -param meant for another day doesn't go unnoticed
+func noParams(f func(string) (helpers.Answer, error)) SolutionFunc {
+	return func(inputFile string, params helpers.Params) (helpers.Answer, error) {
+		if _, err := helpers.Resolve(nil, params); err != nil {
+			return helpers.Answer{}, err
+		}
+		return f(inputFile)
+	}
+}
+
+// plain adapts a solution that takes no parameters and returns a string
+func plain(f func(string) (string, error)) SolutionFunc {
+	return textual(func(inputFile string, params helpers.Params) (string, error) {
+		if _, err := helpers.Resolve(nil, params); err != nil {
+			return "", err
+		}
+		return f(inputFile)
+	})
+}
 
 func main() {
 	day := flag.Int("day", 1, "Advent of Code day (1-12)")
 	part := flag.Int("part", 1, "Part number (1 or 2)")
 	benchmark := flag.Bool("b", false, "Run benchmark (20 iterations)")
+	format := flag.String("format", "text", "Output format: text or json")
+	export := flag.String("export", "", "Day 8: write the merge history to `path`.nwk and path.json")
+	params := helpers.Params{}
+	flag.Func("param", "Solution parameter as name=value (repeatable)", params.Set)
 	flag.Parse()
 
-	fmt.Printf("Running Day %d, Part %d\n", *day, *part)
-	fmt.Println("---")
+	if *format != "text" && *format != "json" {
+		fmt.Fprintf(os.Stderr, "Unknown format %q, expected text or json\n", *format)
+		os.Exit(1)
+	}
+	if *format == "json" && *benchmark {
+		fmt.Fprintln(os.Stderr, "-b prints timings as text and can't be combined with -format json")
+		os.Exit(1)
+	}
+	// Anything besides the result goes to stderr in JSON mode, so stdout
+	// stays a single JSON document
+	status := io.Writer(os.Stdout)
+	if *format == "json" {
+		status = os.Stderr
+	}
+	if *format == "text" {
+		fmt.Printf("Running Day %d, Part %d\n", *day, *part)
+		fmt.Println("---")
+	}
 	solutions := map[int]map[int]SolutionFunc{
 		1: {
-			1: day01.Part1,
-			2: day01.Part2,
+			1: textual(day01.Part1With),
+			2: textual(day01.Part2With),
 		},
 		2: {
-			1: day02.Part1,
-			2: day02.Part2,
+			1: plain(day02.Part1),
+			2: plain(day02.Part2),
 		},
 		3: {
-			1: day03.Part1,
-			2: day03.Part2,
+			1: textual(day03.Part1With),
+			2: textual(day03.Part2With),
 		},
 		4: {
-			1: day04.Part1,
-			2: day04.Part2,
+			1: plain(day04.Part1),
+			2: plain(day04.Part2),
 		},
 		5: {
-			1: day05.Part1,
-			2: day05.Part2,
+			1: plain(day05.Part1),
+			2: plain(day05.Part2),
 		},
 		6: {
-			1: day06.Part1,
-			2: day06.Part2,
+			1: plain(day06.Part1),
+			2: plain(day06.Part2),
 		},
 		7: {
-			1: day07.Part1,
-			2: day07.Part2,
+			1: plain(day07.Part1),
+			2: plain(day07.Part2),
 		},
 		8: {
-			1: day08.Part1,
-			2: day08.Part2,
+			1: textual(day08.Part1With),
+			2: plain(day08.Part2),
 		},
 		9: {
-			1: day09.Part1,
-			2: day09.Part2,
+			1: plain(day09.Part1),
+			2: noParams(day09.Part2Answer),
 		},
 		10: {
-			1: day10.Part1,
-			2: day10.Part2,
+			1: noParams(day10.Part1Answer),
+			2: noParams(day10.Part2Answer),
 		},
 		11: {
-			1: day11.Part1,
-			2: day11.Part2,
+			1: textual(day11.Part1With),
+			2: textual(day11.Part2With),
 		},
 	}
 	inputFile := filepath.Join(fmt.Sprintf("day%02d", *day), "input.txt")
 
 	dayMap, ok := solutions[*day]
 	if !ok {
-		fmt.Printf("Day %d not yet implemented\n", *day)
+		fmt.Fprintf(os.Stderr, "Day %d not yet implemented\n", *day)
 		os.Exit(1)
 	}
 	solution, ok := dayMap[*part]
 	if !ok {
-		fmt.Printf("Day %d Part %d not found\n", *day, *part)
+		fmt.Fprintf(os.Stderr, "Day %d Part %d not found\n", *day, *part)
 		os.Exit(1)
 	}
 
+	if *export != "" {
+		if err := exportDendrogram(status, *day, inputFile, *export); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
 	if *benchmark {
-		runBenchmark(solution, inputFile)
+		runBenchmark(solution, inputFile, params)
 	} else {
 		start := time.Now()
-		res, err := solution(inputFile)
+		res, err := solution(inputFile, params)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		elapsed := time.Since(start)
+		if *format == "json" {
+			err = printJSON(*day, *part, res, elapsed)
+		} else {
+			printText(res, elapsed)
+		}
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 			os.Exit(1)
 		}
-		fmt.Printf("Result: %s\n", res)
-		fmt.Printf("\nCompleted in %v\n", time.Since(start))
 	}
 }
 
-func runBenchmark(solution SolutionFunc, inputFile string) {
+func printText(res helpers.Answer, elapsed time.Duration) {
+	fmt.Printf("Result: %s\n", res)
+	for _, d := range res.Details {
+		fmt.Printf("  %s: %s\n", d.Key, d.Value)
+	}
+	for _, t := range res.Timings {
+		fmt.Printf("  %s took %.3fms\n", t.Name, float64(t.Duration.Microseconds())/1000.0)
+	}
+	fmt.Printf("\nCompleted in %v\n", elapsed)
+}
+
+func printJSON(day, part int, res helpers.Answer, elapsed time.Duration) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(struct {
+		Day       int            `json:"day"`
+		Part      int            `json:"part"`
+		Answer    helpers.Answer `json:"answer"`
+		ElapsedMs float64        `json:"elapsed_ms"`
+	}{day, part, res, float64(elapsed.Microseconds()) / 1000.0})
+}
+
+// exportDendrogram writes the day 8 merge history as Newick and JSON,
+// reporting each file written to status
+func exportDendrogram(status io.Writer, day int, inputFile, path string) error {
+	if day != 8 {
+		return fmt.Errorf("-export is only supported for day 8")
+	}
+	d, err := day08.BuildDendrogram(inputFile)
+	if err != nil {
+		return err
+	}
+
+	write := func(name string, to func(io.Writer) error) error {
+		f, err := os.Create(name)
+		if err != nil {
+			return err
+		}
+		if err := to(f); err != nil {
+			f.Close()
+			return err
+		}
+		fmt.Fprintf(status, "Wrote %s\n", name)
+		return f.Close()
+	}
+	if err := write(path+".nwk", d.WriteNewick); err != nil {
+		return err
+	}
+	return write(path+".json", d.WriteJSON)
+}
+
+func runBenchmark(solution SolutionFunc, inputFile string, params helpers.Params) {
 	const warmupRuns = 10
 	const iterations = 40
 
 	// Warmup phase
 	fmt.Printf("Warming up (%d runs)...\n", warmupRuns)
 	for i := 0; i < warmupRuns; i++ {
-		_, err := solution(inputFile)
+		_, err := solution(inputFile, params)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error during warmup: %v\n", err)
 			os.Exit(1)
@@ -125,7 +246,7 @@ func runBenchmark(solution SolutionFunc, inputFile string) {
 
 	for i := 0; i < iterations; i++ {
 		start := time.Now()
-		_, err := solution(inputFile)
+		_, err := solution(inputFile, params)
 		elapsed := time.Since(start)
 		times[i] = elapsed
 